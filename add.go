@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
+)
+
+// addFeed implements `feeder add <url> [name]`, detecting the feed type and
+// falling back to the feed's own title when no name is given. With
+// -selectors the URL is an HTML page scraped as a custom feed instead, and
+// adding it again replaces the selectors.
+func addFeed(ctx context.Context, queries *database.Queries, opts fetch.Options, args []string) error {
+	fs := flag.NewFlagSet("add", flag.ExitOnError)
+	basicAuth := fs.String("basic-auth", "", "`user:pass` for HTTP Basic auth")
+	authHeader := fs.String("auth-header", "", "`header` sent with every request, e.g. \"Authorization: Bearer ${TOKEN}\"")
+	interval := fs.Duration("interval", 0, "minimum time between fetches of this feed, e.g. 6h; 0 fetches on every run")
+	since := fs.String("since", "", "only store posts published after this `cutoff`, a duration such as 30d or 12h, or a date such as 2024-01-31")
+	backfill := fs.Int("backfill", 0, "fetch the feed right away, following up to `N` older pages to import its history")
+	dateFormat := fs.String("date-format", "", "Go time `layout` of the feed's dates, e.g. \"Mon Jan 2 15:04 2006\", for dates that aren't recognized")
+	selectors := fs.String("selectors", "", "`JSON` selectors scraping an HTML page as a custom feed, e.g. '{\"item\":\"article\",\"title\":\"h2\",\"link\":\"a\",\"date\":\"time\"}'")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder add [flags] <url> [name]")
+		fmt.Fprint(fs.Output(), "\nCredentials are stored in plaintext. Use ${NAME} to read them from the\nenvironment on every fetch instead.\n\n")
+		fs.PrintDefaults()
+	}
+	positional := parseInterspersed(fs, args)
+	if len(positional) < 1 || len(positional) > 2 {
+		fs.Usage()
+		return errors.New("add expects a URL and an optional name")
+	}
+	url := positional[0]
+	if *interval < 0 {
+		return errors.New("-interval can't be negative")
+	}
+	if *backfill < 0 {
+		return errors.New("-backfill can't be negative")
+	}
+	// A layout set by hand is never replaced by the detected one
+	var dateFormatUserSet int64
+	if *dateFormat != "" {
+		if err := fetch.ValidateDateLayout(*dateFormat); err != nil {
+			return err
+		}
+		dateFormatUserSet = 1
+	}
+	if *selectors != "" {
+		if err := fetch.ValidateCustomSelectors(*selectors); err != nil {
+			return fmt.Errorf("invalid -selectors: %w", err)
+		}
+	}
+	var storeSince string
+	if *since != "" {
+		cutoff, err := parseSince(*since, time.Now())
+		if err != nil {
+			return err
+		}
+		storeSince = cutoff.Format(time.RFC3339)
+	}
+
+	auth := fetch.Auth{Header: *authHeader}
+	if *basicAuth != "" {
+		user, pass, ok := strings.Cut(*basicAuth, ":")
+		if !ok {
+			return errors.New("-basic-auth expects user:pass")
+		}
+		auth.User, auth.Pass = user, pass
+	}
+
+	if feed, err := queries.GetFeedByURL(ctx, url); err == nil {
+		if *selectors == "" {
+			return fmt.Errorf("feed %s already exists", url)
+		}
+		return setSelectors(ctx, queries, feed, *selectors)
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to look up feed: %w", err)
+	}
+
+	// A scraped page isn't a feed, there's nothing to detect
+	detected := fetch.DetectedFeed{URL: url, Type: "custom"}
+	if *selectors == "" {
+		var err error
+		detected, err = fetch.Detect(ctx, opts, url, auth)
+		if err != nil {
+			return fmt.Errorf("failed to detect feed type: %w", err)
+		}
+	}
+	if detected.URL != url {
+		fmt.Printf("Found feed %s on %s\n", detected.URL, url)
+		url = detected.URL
+		if _, err := queries.GetFeedByURL(ctx, url); err == nil {
+			return fmt.Errorf("feed %s already exists", url)
+		} else if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to look up feed: %w", err)
+		}
+	}
+	feedType := detected.Type
+
+	var name string
+	if len(positional) > 1 {
+		name = positional[1]
+	}
+	if name == "" {
+		name = detected.Title
+	}
+	if name == "" {
+		name = url
+	}
+
+	err := queries.CreateFeed(ctx, database.CreateFeedParams{
+		Name:       name,
+		Url:        url,
+		FeedType:   feedType,
+		AuthUser:   sql.NullString{String: auth.User, Valid: auth.User != ""},
+		AuthPass:   sql.NullString{String: auth.Pass, Valid: auth.User != ""},
+		AuthHeader: sql.NullString{String: auth.Header, Valid: auth.Header != ""},
+		RefreshIntervalSeconds: sql.NullInt64{
+			Int64: int64(interval.Round(time.Second) / time.Second),
+			Valid: *interval > 0,
+		},
+		StoreSince:        sql.NullString{String: storeSince, Valid: storeSince != ""},
+		DateFormat:        sql.NullString{String: *dateFormat, Valid: *dateFormat != ""},
+		DateFormatUserSet: sql.NullInt64{Int64: dateFormatUserSet, Valid: true},
+		CustomSelectors:   sql.NullString{String: *selectors, Valid: *selectors != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add feed: %w", err)
+	}
+
+	fmt.Printf("Added %s feed %q\n", feedType, name)
+	if storeSince != "" {
+		fmt.Printf("Posts published before %s will be skipped\n", storeSince)
+	}
+	if *backfill > 0 {
+		return backfillFeed(ctx, queries, opts, url, *backfill)
+	}
+	return nil
+}
+
+// setSelectors replaces the selectors of an existing feed, turning it into a
+// custom feed if it wasn't one
+func setSelectors(ctx context.Context, queries *database.Queries, feed database.Feed, selectors string) error {
+	err := queries.SetFeedCustomSelectors(ctx, database.SetFeedCustomSelectorsParams{
+		CustomSelectors: sql.NullString{String: selectors, Valid: true},
+		ID:              feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set selectors: %w", err)
+	}
+	fmt.Printf("Updated the selectors of %q\n", feed.Name)
+	return nil
+}
+
+// backfillFeed fetches a newly added feed along with up to pages of its
+// older entries, so its history is there before the first regular fetch
+func backfillFeed(ctx context.Context, queries *database.Queries, opts fetch.Options, url string, pages int) error {
+	feed, err := queries.GetFeedByURL(ctx, url)
+	if err != nil {
+		return fmt.Errorf("failed to read feed: %w", err)
+	}
+	opts.Backfill = pages
+	added, err := fetch.FetchAndStore(ctx, queries, feed, opts)
+	if err != nil {
+		return fmt.Errorf("failed to backfill feed: %w", err)
+	}
+	fmt.Printf("Stored %d posts\n", added)
+	return nil
+}
+
+// parseSince reads the cutoff given to -since, either a duration before now
+// (time.ParseDuration's units plus d for days) or a date with an optional
+// time, which is taken as local time unless it carries an offset.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		if d < 0 {
+			return time.Time{}, errors.New("-since can't be negative")
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("-since expects a duration such as 30d or a date such as 2024-01-31, got %q", value)
+}
+
+// parseInterspersed parses args allowing flags to follow positional
+// arguments, as in `feeder add <url> -basic-auth user:pass`, and returns the
+// positional arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return positional
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
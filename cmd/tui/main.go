@@ -2,19 +2,32 @@ package main
 
 import (
 	"context"
-	"database/sql"
 	"log"
 
+	"github.com/aaronzipp/feeder/config"
 	"github.com/aaronzipp/feeder/database"
 	"github.com/aaronzipp/feeder/tui"
-
-	_ "modernc.org/sqlite"
 )
 
 func main() {
 	ctx := context.Background()
 
-	db, err := sql.Open("sqlite", "database/feeder.db")
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	theme, err := tui.LookupTheme(cfg.Theme)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts, err := cfg.FetchOptions()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err := database.Open(ctx, cfg.Database)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -22,7 +35,7 @@ func main() {
 
 	queries := database.New(db)
 
-	if err := tui.Run(ctx, queries); err != nil {
+	if err := tui.Run(ctx, queries, opts, cfg.Sort, cfg.BrowserCommand, theme, cfg.FeedWidth); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aaronzipp/feeder/config"
+	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
+)
+
+// runDaemon implements `feeder daemon`, fetching all enabled feeds every
+// interval until it receives SIGINT or SIGTERM. Feeds being fetched when
+// the signal arrives are finished and stored before it returns.
+func runDaemon(
+	ctx context.Context,
+	db *sql.DB,
+	queries *database.Queries,
+	opts fetch.Options,
+	cfg config.Config,
+	args []string,
+) error {
+	fs := flag.NewFlagSet("daemon", flag.ExitOnError)
+	interval := fs.Duration("interval", 15*time.Minute, "time between fetches")
+	minInterval := fs.Duration("min-interval", 5*time.Minute, "skip feeds checked more recently than this")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder daemon [-interval 15m] [-min-interval 5m]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("daemon takes no arguments")
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("daemon needs a positive interval, got %s", *interval)
+	}
+	opts.MinInterval = *minInterval
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	ticker := time.NewTicker(*interval)
+	defer ticker.Stop()
+
+	slog.Info("Starting daemon", "interval", *interval, "min_interval", *minInterval)
+	for {
+		if cfg.OPMLDir != "" {
+			if err := autoSync(ctx, db, queries, opts, cfg.OPMLDir, cfg.OPMLPruneMissing); err != nil {
+				slog.Error("Sync failed", "err", err)
+			}
+		}
+		if _, err := fetch.Refresh(ctx, queries, opts); err != nil && ctx.Err() == nil {
+			slog.Error("Refresh failed", "err", err)
+		}
+		if cfg.AutoPrune && ctx.Err() == nil {
+			if err := autoPrune(ctx, db, queries, cfg.PruneDays); err != nil {
+				slog.Error("Prune failed", "err", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("Stopping daemon")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
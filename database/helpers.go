@@ -3,31 +3,366 @@ package database
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // PostWithFeed is an alias for the unified post with feed type
 type PostWithFeed = ListPostsWithFeedFilteredRow
 
-// ListInbox returns all non-archived, non-starred posts with feed information
-func (q *Queries) ListInbox(ctx context.Context) ([]PostWithFeed, error) {
+// PostSort is the order in which post lists are returned
+type PostSort string
+
+const (
+	SortNewest PostSort = "newest"
+	SortOldest PostSort = "oldest"
+	SortFeed   PostSort = "feed"
+	// SortFirstSeen orders by when posts were stored, newest first, so
+	// backdated or republished posts can't jump around
+	SortFirstSeen PostSort = "first-seen"
+)
+
+// Next returns the sort mode that follows s, wrapping around
+func (s PostSort) Next() PostSort {
+	switch s {
+	case SortNewest:
+		return SortOldest
+	case SortOldest:
+		return SortFeed
+	case SortFeed:
+		return SortFirstSeen
+	default:
+		return SortNewest
+	}
+}
+
+// Valid reports whether s is one of the known sort modes
+func (s PostSort) Valid() bool {
+	switch s {
+	case SortNewest, SortOldest, SortFeed, SortFirstSeen:
+		return true
+	default:
+		return false
+	}
+}
+
+// ListOptions narrows and orders the post lists below
+type ListOptions struct {
+	Sort PostSort
+	// FeedID limits the list to a single feed; zero means all feeds
+	FeedID int64
+	// Category limits the list to posts tagged with it; empty means all
+	Category string
+	// Limit caps how many posts are returned, starting after the first
+	// Offset; zero returns them all
+	Limit  int
+	Offset int
+}
+
+func (o ListOptions) sort() string {
+	if o.Sort == "" {
+		return string(SortNewest)
+	}
+	return string(o.Sort)
+}
+
+// limit maps no limit to SQLite's -1
+func (o ListOptions) limit() int64 {
+	if o.Limit <= 0 {
+		return -1
+	}
+	return int64(o.Limit)
+}
+
+func (o ListOptions) category() interface{} {
+	if o.Category == "" {
+		return nil
+	}
+	return o.Category
+}
+
+func (o ListOptions) feedID() interface{} {
+	if o.FeedID == 0 {
+		return nil
+	}
+	return sql.NullInt64{Int64: o.FeedID, Valid: true}
+}
+
+// ListInbox returns all non-archived, non-starred posts with feed
+// information, leaving out posts that are snoozed
+func (q *Queries) ListInbox(ctx context.Context, opts ListOptions) ([]PostWithFeed, error) {
 	return q.ListPostsWithFeedFiltered(ctx, ListPostsWithFeedFilteredParams{
 		IsArchived: sql.NullInt64{Int64: 0, Valid: true},
 		IsStarred:  sql.NullInt64{Int64: 0, Valid: true}, // Exclude starred posts
+		FeedID:     opts.feedID(),
+		Category:   opts.category(),
+		AwakeAt:    now(),
+		Sort:       opts.sort(),
+		Limit:      opts.limit(),
+		Offset:     int64(opts.Offset),
 	})
 }
 
+// ListNew returns all non-archived, non-snoozed posts first seen after
+// since, starred or not, with feed information
+func (q *Queries) ListNew(ctx context.Context, since time.Time, opts ListOptions) ([]PostWithFeed, error) {
+	return q.ListPostsWithFeedFiltered(ctx, ListPostsWithFeedFilteredParams{
+		IsArchived: sql.NullInt64{Int64: 0, Valid: true},
+		IsStarred:  nil, // New posts stay new after being starred
+		FeedID:     opts.feedID(),
+		Category:   opts.category(),
+		Since:      since.Format(time.RFC3339),
+		AwakeAt:    now(),
+		Sort:       opts.sort(),
+		Limit:      opts.limit(),
+		Offset:     int64(opts.Offset),
+	})
+}
+
+// InTx runs fn with queries that use a new transaction, committing it when
+// fn succeeds and rolling it back otherwise. Queries that already belong to
+// a transaction run fn within it.
+func (q *Queries) InTx(ctx context.Context, fn func(*Queries) error) error {
+	db, ok := q.db.(*sql.DB)
+	if !ok {
+		return fn(q)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RolledBack runs fn with queries that use a new transaction and always
+// rolls it back, so what fn would write can be inspected without keeping
+// it. It returns an error only when the transaction can't be started.
+func (q *Queries) RolledBack(ctx context.Context, fn func(*Queries)) error {
+	db, ok := q.db.(*sql.DB)
+	if !ok {
+		return errors.New("can't roll back queries that already run in a transaction")
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	fn(q.WithTx(tx))
+	return nil
+}
+
+// lastOpenedKey is the setting holding the time the user last caught up
+const lastOpenedKey = "last_opened_at"
+
+// LastOpened returns when the user last marked everything as seen, or the
+// zero time if they never have
+func (q *Queries) LastOpened(ctx context.Context) (time.Time, error) {
+	value, err := q.GetSetting(ctx, lastOpenedKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// SetLastOpened records t as the time the user last caught up
+func (q *Queries) SetLastOpened(ctx context.Context, t time.Time) error {
+	return q.SetSetting(ctx, SetSettingParams{
+		Key:   lastOpenedKey,
+		Value: t.Format(time.RFC3339),
+	})
+}
+
+// Settings holding the screen and post the TUI showed when it was closed
+const (
+	lastScreenKey = "last_screen"
+	lastPostKey   = "last_post_id"
+)
+
+// LastView returns the screen and selected post the TUI was closed on, or
+// empty values if it never was
+func (q *Queries) LastView(ctx context.Context) (string, int64, error) {
+	screen, err := q.GetSetting(ctx, lastScreenKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	value, err := q.GetSetting(ctx, lastPostKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return screen, 0, nil
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	// A post ID that doesn't parse only loses the cursor position
+	postID, _ := strconv.ParseInt(value, 10, 64)
+	return screen, postID, nil
+}
+
+// SetLastView records the screen and selected post the TUI was closed on;
+// postID is 0 when no post was selected
+func (q *Queries) SetLastView(ctx context.Context, screen string, postID int64) error {
+	return q.InTx(ctx, func(q *Queries) error {
+		err := q.SetSetting(ctx, SetSettingParams{Key: lastScreenKey, Value: screen})
+		if err != nil {
+			return err
+		}
+		return q.SetSetting(ctx, SetSettingParams{
+			Key:   lastPostKey,
+			Value: strconv.FormatInt(postID, 10),
+		})
+	})
+}
+
+// ArchiveInbox archives every post in the inbox that matches the filters in
+// opts and returns how many were archived. Snoozed posts aren't in the inbox
+// and are left alone.
+func (q *Queries) ArchiveInbox(ctx context.Context, opts ListOptions) (int64, error) {
+	return q.ArchiveAllInbox(ctx, ArchiveAllInboxParams{
+		FeedID:   opts.feedID(),
+		Category: opts.category(),
+		Now:      now(),
+	})
+}
+
+// DeleteFeedCascade deletes a feed along with its posts, mutes and the
+// GUIDs of its posts deleted for good, all in one transaction, returning
+// how many posts were deleted
+func (q *Queries) DeleteFeedCascade(ctx context.Context, feedID int64) (int64, error) {
+	var removed int64
+	err := q.InTx(ctx, func(q *Queries) error {
+		var err error
+		if removed, err = q.DeletePostsByFeed(ctx, feedID); err != nil {
+			return fmt.Errorf("failed to delete posts: %w", err)
+		}
+		if err := q.DeleteMutesByFeed(ctx, sql.NullInt64{Int64: feedID, Valid: true}); err != nil {
+			return fmt.Errorf("failed to delete mutes: %w", err)
+		}
+		if err := q.DeleteDeletedPostsByFeed(ctx, feedID); err != nil {
+			return fmt.Errorf("failed to forget deleted posts: %w", err)
+		}
+		if err := q.DeleteFeed(ctx, feedID); err != nil {
+			return fmt.Errorf("failed to delete feed: %w", err)
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// DeletePostForGood removes a post from the database, remembering its GUID
+// so fetching its feed again doesn't store it anew
+func (q *Queries) DeletePostForGood(ctx context.Context, postID int64) error {
+	return q.InTx(ctx, func(q *Queries) error {
+		if err := q.CreateDeletedPost(ctx, postID); err != nil {
+			return err
+		}
+		return q.DeletePost(ctx, postID)
+	})
+}
+
+// Prune deletes archived, non-starred posts published before before and
+// remembers their GUIDs like DeletePostForGood, so posts still in their
+// feed don't come back as new until they leave it. It returns how many
+// posts were deleted.
+func (q *Queries) Prune(ctx context.Context, before string) (int64, error) {
+	var removed int64
+	err := q.InTx(ctx, func(q *Queries) error {
+		if err := q.CreatePrunedDeletedPosts(ctx, before); err != nil {
+			return err
+		}
+		var err error
+		removed, err = q.PrunePosts(ctx, before)
+		return err
+	})
+	return removed, err
+}
+
+// Snooze hides a post from the inbox until the given time
+func (q *Queries) Snooze(ctx context.Context, postID int64, until time.Time) error {
+	return q.SnoozePost(ctx, SnoozePostParams{
+		SnoozeUntil: sql.NullString{String: until.Format(time.RFC3339), Valid: true},
+		ID:          postID,
+	})
+}
+
+// now is the current time in the format dates are stored in
+func now() string {
+	return time.Now().Format(time.RFC3339)
+}
+
+// ScreenCounts returns how many posts the inbox, starred and archive screens
+// hold without filters, and how many feeds there are
+func (q *Queries) ScreenCounts(ctx context.Context) (CountScreensRow, error) {
+	return q.CountScreens(ctx, now())
+}
+
 // ListArchive returns all archived posts with feed information
-func (q *Queries) ListArchive(ctx context.Context) ([]PostWithFeed, error) {
+func (q *Queries) ListArchive(ctx context.Context, opts ListOptions) ([]PostWithFeed, error) {
 	return q.ListPostsWithFeedFiltered(ctx, ListPostsWithFeedFilteredParams{
 		IsArchived: sql.NullInt64{Int64: 1, Valid: true},
 		IsStarred:  nil, // No filter on starred
+		FeedID:     opts.feedID(),
+		Category:   opts.category(),
+		Sort:       opts.sort(),
+		Limit:      opts.limit(),
+		Offset:     int64(opts.Offset),
 	})
 }
 
 // ListStarred returns all starred posts with feed information
-func (q *Queries) ListStarred(ctx context.Context) ([]PostWithFeed, error) {
+func (q *Queries) ListStarred(ctx context.Context, opts ListOptions) ([]PostWithFeed, error) {
 	return q.ListPostsWithFeedFiltered(ctx, ListPostsWithFeedFilteredParams{
 		IsArchived: nil, // No filter on archived
 		IsStarred:  sql.NullInt64{Int64: 1, Valid: true},
+		FeedID:     opts.feedID(),
+		Category:   opts.category(),
+		Sort:       opts.sort(),
+		Limit:      opts.limit(),
+		Offset:     int64(opts.Offset),
 	})
 }
+
+// Search finds posts on every screen whose title, summary or author match
+// all words of input, best and most recent matches first
+func (q *Queries) Search(ctx context.Context, input string) ([]PostWithFeed, error) {
+	query := searchQuery(input)
+	if query == "" {
+		return nil, nil
+	}
+
+	rows, err := q.SearchPosts(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	posts := make([]PostWithFeed, len(rows))
+	for i, row := range rows {
+		posts[i] = PostWithFeed(row)
+	}
+	return posts, nil
+}
+
+// searchQuery turns free text into an FTS5 query matching every word as a
+// prefix, quoting the words so FTS5 operators and punctuation can't cause
+// syntax errors
+func searchQuery(input string) string {
+	words := strings.Fields(input)
+	for i, word := range words {
+		words[i] = `"` + strings.ReplaceAll(word, `"`, `""`) + `"*`
+	}
+	return strings.Join(words, " ")
+}
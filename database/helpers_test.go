@@ -0,0 +1,73 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// BenchmarkCreatePosts compares storing a feed's 100 posts one implicit
+// transaction at a time with storing them in one transaction through InTx
+func BenchmarkCreatePosts(b *testing.B) {
+	const posts = 100
+
+	ctx := context.Background()
+	db, err := Open(ctx, b.TempDir()+"/feeder.db")
+	if err != nil {
+		b.Fatalf("Open: %v", err)
+	}
+	defer db.Close()
+	queries := New(db)
+
+	feedID := int64(0)
+	// Every round stores new posts into a feed of its own, so none of them
+	// are skipped as duplicates
+	createPosts := func(queries *Queries) error {
+		for i := range posts {
+			_, err := queries.CreatePost(ctx, CreatePostParams{
+				Title:       fmt.Sprintf("Post %d", i),
+				Url:         fmt.Sprintf("https://example.com/%d/%d", feedID, i),
+				PublishedAt: "2024-01-01T00:00:00Z",
+				FeedID:      feedID,
+				Guid:        fmt.Sprint(i),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	newFeed := func(b *testing.B) {
+		feedID++
+		err := queries.CreateFeed(ctx, CreateFeedParams{
+			Name:     fmt.Sprint(feedID),
+			Url:      fmt.Sprintf("https://example.com/%d/feed", feedID),
+			FeedType: "rss",
+		})
+		if err != nil {
+			b.Fatalf("CreateFeed: %v", err)
+		}
+	}
+
+	b.Run("separately", func(b *testing.B) {
+		for b.Loop() {
+			b.StopTimer()
+			newFeed(b)
+			b.StartTimer()
+			if err := createPosts(queries); err != nil {
+				b.Fatalf("CreatePost: %v", err)
+			}
+		}
+	})
+
+	b.Run("InTx", func(b *testing.B) {
+		for b.Loop() {
+			b.StopTimer()
+			newFeed(b)
+			b.StartTimer()
+			if err := queries.InTx(ctx, createPosts); err != nil {
+				b.Fatalf("CreatePost: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// schema.sql always describes the latest version and is used as is for new
+// databases. Existing databases are upgraded by the files in migrations,
+// where the nth file (in name order) moves a database to version n+1.
+//
+//go:embed schema.sql migrations/*.sql
+var sqlFiles embed.FS
+
+// Migrate creates the tables of a new database or upgrades an existing one
+// to the latest schema. The version is tracked in SQLite's user_version, so
+// running it again is a no-op.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrations, err := fs.Glob(sqlFiles, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(migrations)
+	latest := len(migrations) + 1
+
+	var version int
+	if err := db.QueryRowContext(ctx, "pragma user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if version == 0 {
+		var tables int
+		err := db.QueryRowContext(
+			ctx,
+			"select count(*) from sqlite_master where type = 'table' and name = 'feed'",
+		).Scan(&tables)
+		if err != nil {
+			return fmt.Errorf("failed to inspect database: %w", err)
+		}
+		if tables == 0 {
+			return applySQL(ctx, db, "schema.sql", latest)
+		}
+		// Databases set up by hand before versioning started out with the
+		// original schema
+		version = 1
+	}
+
+	for ; version < latest; version++ {
+		if err := applySQL(ctx, db, migrations[version-1], version+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySQL runs an embedded SQL file and records the resulting version in
+// one transaction.
+func applySQL(ctx context.Context, db *sql.DB, name string, version int) error {
+	script, err := sqlFiles.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+		return fmt.Errorf("failed to apply %s: %w", name, err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf("pragma user_version = %d", version)); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return tx.Commit()
+}
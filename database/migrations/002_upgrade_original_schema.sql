@@ -0,0 +1,115 @@
+-- Databases created from the original schema predate the cache, error,
+-- selector and read-state columns as well as the GUID and unique URL
+-- constraints. SQLite can't add constraints in place, so both tables are
+-- rebuilt.
+create table feed_new (
+  id integer primary key,
+  name text not null,
+  last_updated_at text,
+  url text not null unique,
+  feed_type text check (feed_type in ('rss', 'atom', 'json', 'custom')) not null,
+  date_format text,
+  etag text,
+  last_modified text,
+  last_checked_at text,
+  custom_selectors text,
+  last_error text,
+  last_error_at text,
+  is_enabled integer default 1
+);
+
+-- Nothing stopped the same URL from being added twice, so duplicate feeds
+-- are merged into the oldest one
+insert into feed_new (id, name, last_updated_at, url, feed_type, date_format)
+select
+  id,
+  name,
+  last_updated_at,
+  url,
+  feed_type,
+  date_format
+from
+  feed
+where
+  id in (
+    select
+      min(id)
+    from
+      feed
+    group by
+      url
+  );
+
+create table post_new (
+  id integer primary key,
+  title text not null,
+  url text not null,
+  published_at text not null,
+  feed_id integer not null,
+  is_archived integer default 0,
+  is_starred integer default 0,
+  guid text not null,
+  summary text,
+  is_read integer default 0,
+  foreign key (feed_id) references feed (id) on delete cascade,
+  unique (url, feed_id),
+  unique (feed_id, guid)
+);
+
+-- Posts were deduplicated on their URL before GUIDs were stored. Posts of a
+-- merged feed move to the feed it was merged into, keeping the oldest copy
+-- of posts both had, starred if either copy was.
+with
+  merged as (
+    select
+      post.*,
+      coalesce(
+        (
+          select
+            min(kept.id)
+          from
+            feed kept
+            join feed on feed.url = kept.url
+          where
+            feed.id = post.feed_id
+        ),
+        post.feed_id
+      ) as merged_feed_id
+    from
+      post
+  )
+insert into post_new (id, title, url, published_at, feed_id, is_archived, is_starred, guid)
+select
+  id,
+  title,
+  url,
+  published_at,
+  merged_feed_id,
+  is_archived,
+  (
+    select
+      max(copy.is_starred)
+    from
+      merged copy
+    where
+      copy.merged_feed_id = merged.merged_feed_id
+      and copy.url = merged.url
+  ),
+  url
+from
+  merged
+where
+  id in (
+    select
+      min(id)
+    from
+      merged
+    group by
+      merged_feed_id,
+      url
+  );
+
+drop table post;
+drop table feed;
+alter table feed_new rename to feed;
+alter table post_new rename to post;
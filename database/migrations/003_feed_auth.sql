@@ -0,0 +1,3 @@
+alter table feed add column auth_user text;
+alter table feed add column auth_pass text;
+alter table feed add column auth_header text;
@@ -0,0 +1 @@
+alter table post add column categories text;
@@ -0,0 +1 @@
+alter table post add column date_estimated integer default 0;
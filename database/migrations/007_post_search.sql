@@ -0,0 +1,26 @@
+create virtual table post_search using fts5 (
+  title,
+  summary,
+  author,
+  content = 'post',
+  content_rowid = 'id'
+);
+
+create trigger post_search_insert after insert on post begin
+  insert into post_search (rowid, title, summary, author)
+  values (new.id, new.title, new.summary, new.author);
+end;
+
+create trigger post_search_delete after delete on post begin
+  insert into post_search (post_search, rowid, title, summary, author)
+  values ('delete', old.id, old.title, old.summary, old.author);
+end;
+
+create trigger post_search_update after update of title, summary, author on post begin
+  insert into post_search (post_search, rowid, title, summary, author)
+  values ('delete', old.id, old.title, old.summary, old.author);
+  insert into post_search (rowid, title, summary, author)
+  values (new.id, new.title, new.summary, new.author);
+end;
+
+insert into post_search (post_search) values ('rebuild');
@@ -0,0 +1,5 @@
+alter table post add column created_at text;
+
+-- Posts stored before this column existed were first seen no later than
+-- their publication date as far as we know
+update post set created_at = published_at;
@@ -0,0 +1 @@
+alter table feed add column refresh_interval_seconds integer;
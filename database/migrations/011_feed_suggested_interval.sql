@@ -0,0 +1 @@
+alter table feed add column suggested_interval_seconds integer;
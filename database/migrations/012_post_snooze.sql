@@ -0,0 +1 @@
+alter table post add column snooze_until text;
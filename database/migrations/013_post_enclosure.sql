@@ -0,0 +1,3 @@
+alter table post add column enclosure_url text;
+alter table post add column enclosure_type text;
+alter table post add column enclosure_length integer;
@@ -0,0 +1,67 @@
+-- RSS 1.0 feeds are stored as 'rdf'. SQLite can't change a check constraint
+-- in place, so the feed table is rebuilt.
+create table feed_new (
+  id integer primary key,
+  name text not null,
+  last_updated_at text,
+  url text not null unique,
+  feed_type text check (feed_type in ('rss', 'rdf', 'atom', 'json', 'custom')) not null,
+  date_format text,
+  etag text,
+  last_modified text,
+  last_checked_at text,
+  custom_selectors text,
+  last_error text,
+  last_error_at text,
+  is_enabled integer default 1,
+  auth_user text,
+  auth_pass text,
+  auth_header text,
+  refresh_interval_seconds integer,
+  suggested_interval_seconds integer
+);
+
+insert into feed_new (
+  id,
+  name,
+  last_updated_at,
+  url,
+  feed_type,
+  date_format,
+  etag,
+  last_modified,
+  last_checked_at,
+  custom_selectors,
+  last_error,
+  last_error_at,
+  is_enabled,
+  auth_user,
+  auth_pass,
+  auth_header,
+  refresh_interval_seconds,
+  suggested_interval_seconds
+)
+select
+  id,
+  name,
+  last_updated_at,
+  url,
+  feed_type,
+  date_format,
+  etag,
+  last_modified,
+  last_checked_at,
+  custom_selectors,
+  last_error,
+  last_error_at,
+  is_enabled,
+  auth_user,
+  auth_pass,
+  auth_header,
+  refresh_interval_seconds,
+  suggested_interval_seconds
+from
+  feed;
+
+drop table feed;
+alter table feed_new rename to feed;
@@ -0,0 +1 @@
+alter table feed add column store_since text;
@@ -0,0 +1,6 @@
+create table mute (
+  id integer primary key,
+  pattern text not null,
+  feed_id integer,
+  foreign key (feed_id) references feed (id) on delete cascade
+);
@@ -0,0 +1,2 @@
+alter table post add column enclosure_duration integer;
+alter table post add column image_url text;
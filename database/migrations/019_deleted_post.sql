@@ -0,0 +1,6 @@
+create table deleted_post (
+  feed_id integer not null,
+  guid text not null,
+  primary key (feed_id, guid),
+  foreign key (feed_id) references feed (id) on delete cascade
+);
@@ -0,0 +1,2 @@
+alter table feed add column title text;
+alter table feed add column description text;
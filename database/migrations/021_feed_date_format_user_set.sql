@@ -0,0 +1 @@
+alter table feed add column date_format_user_set integer default 0;
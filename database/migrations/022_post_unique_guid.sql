@@ -0,0 +1,111 @@
+-- Posts were also unique on (url, feed_id), which silently dropped items
+-- with their own GUID that share a link, such as podcast episodes linking
+-- to the show page. SQLite can't drop a constraint in place, so the post
+-- table is rebuilt, keeping the first post of any GUID stored twice.
+create table post_new (
+  id integer primary key,
+  title text not null,
+  url text not null,
+  published_at text not null,
+  feed_id integer not null,
+  is_archived integer default 0,
+  is_starred integer default 0,
+  guid text not null,
+  summary text,
+  is_read integer default 0,
+  author text,
+  categories text,
+  date_estimated integer default 0,
+  created_at text,
+  snooze_until text,
+  enclosure_url text,
+  enclosure_type text,
+  enclosure_length integer,
+  enclosure_duration integer,
+  image_url text,
+  content text,
+  foreign key (feed_id) references feed (id) on delete cascade,
+  unique (feed_id, guid)
+);
+
+insert into post_new (
+  id,
+  title,
+  url,
+  published_at,
+  feed_id,
+  is_archived,
+  is_starred,
+  guid,
+  summary,
+  is_read,
+  author,
+  categories,
+  date_estimated,
+  created_at,
+  snooze_until,
+  enclosure_url,
+  enclosure_type,
+  enclosure_length,
+  enclosure_duration,
+  image_url,
+  content
+)
+select
+  id,
+  title,
+  url,
+  published_at,
+  feed_id,
+  is_archived,
+  is_starred,
+  guid,
+  summary,
+  is_read,
+  author,
+  categories,
+  date_estimated,
+  created_at,
+  snooze_until,
+  enclosure_url,
+  enclosure_type,
+  enclosure_length,
+  enclosure_duration,
+  image_url,
+  content
+from
+  post
+where
+  id in (
+    select
+      min(id)
+    from
+      post
+    group by
+      feed_id,
+      guid
+  );
+
+drop table post;
+alter table post_new rename to post;
+
+-- The search triggers went with the old table, and the index is rebuilt in
+-- case any duplicate was left out
+create trigger post_search_insert after insert on post begin
+  insert into post_search (rowid, title, summary, author)
+  values (new.id, new.title, new.summary, new.author);
+end;
+
+create trigger post_search_delete after delete on post begin
+  insert into post_search (post_search, rowid, title, summary, author)
+  values ('delete', old.id, old.title, old.summary, old.author);
+end;
+
+create trigger post_search_update after update of title, summary, author on post begin
+  insert into post_search (post_search, rowid, title, summary, author)
+  values ('delete', old.id, old.title, old.summary, old.author);
+  insert into post_search (rowid, title, summary, author)
+  values (new.id, new.title, new.summary, new.author);
+end;
+
+insert into post_search (post_search) values ('rebuild');
@@ -9,20 +9,61 @@ import (
 )
 
 type Feed struct {
-	ID            int64
-	Name          string
-	LastUpdatedAt sql.NullString
-	Url           string
-	FeedType      string
-	DateFormat    sql.NullString
+	ID                       int64
+	Name                     string
+	LastUpdatedAt            sql.NullString
+	Url                      string
+	FeedType                 string
+	DateFormat               sql.NullString
+	Etag                     sql.NullString
+	LastModified             sql.NullString
+	LastCheckedAt            sql.NullString
+	CustomSelectors          sql.NullString
+	LastError                sql.NullString
+	LastErrorAt              sql.NullString
+	IsEnabled                sql.NullInt64
+	AuthUser                 sql.NullString
+	AuthPass                 sql.NullString
+	AuthHeader               sql.NullString
+	RefreshIntervalSeconds   sql.NullInt64
+	SuggestedIntervalSeconds sql.NullInt64
+	StoreSince               sql.NullString
+	Title                    sql.NullString
+	Description              sql.NullString
+	DateFormatUserSet        sql.NullInt64
+}
+
+type Setting struct {
+	Key   string
+	Value string
+}
+
+type Mute struct {
+	ID      int64
+	Pattern string
+	FeedID  sql.NullInt64
 }
 
 type Post struct {
-	ID          int64
-	Title       string
-	Url         string
-	PublishedAt string
-	FeedID      int64
-	IsArchived  sql.NullInt64
-	IsStarred   sql.NullInt64
+	ID                int64
+	Title             string
+	Url               string
+	PublishedAt       string
+	FeedID            int64
+	IsArchived        sql.NullInt64
+	IsStarred         sql.NullInt64
+	Guid              string
+	Summary           sql.NullString
+	IsRead            sql.NullInt64
+	Author            sql.NullString
+	Categories        sql.NullString
+	DateEstimated     sql.NullInt64
+	CreatedAt         sql.NullString
+	SnoozeUntil       sql.NullString
+	EnclosureUrl      sql.NullString
+	EnclosureType     sql.NullString
+	EnclosureLength   sql.NullInt64
+	EnclosureDuration sql.NullInt64
+	ImageUrl          sql.NullString
+	Content           sql.NullString
 }
@@ -0,0 +1,50 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// pragmas are applied to every connection. The TUI and a fetch run from
+// cron often use the database at the same time: busy_timeout makes a
+// connection wait up to five seconds for a lock held by the other process
+// instead of failing with "database is locked", and WAL lets readers carry
+// on while a writer is busy.
+const pragmas = "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+
+// Open opens the SQLite database at path, creating its directory if needed,
+// and migrates it to the latest schema.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	db, err := sql.Open("sqlite", path+pragmas)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite has a single writer; one connection queues the concurrent
+	// writes of a fetch within this process rather than letting them race
+	// for the lock
+	db.SetMaxOpenConns(1)
+
+	if err := Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return db, nil
+}
+
+// IsBusy reports whether err is SQLite giving up on a lock that another
+// connection held for longer than busy_timeout
+func IsBusy(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code()&0xff == sqlite3.SQLITE_BUSY
+}
@@ -4,11 +4,65 @@ select
 from
   feed;
 
+-- name: ListEnabledFeeds :many
+select
+  *
+from
+  feed
+where
+  is_enabled = 1;
+
+-- name: ListFailedFeeds :many
+select
+  *
+from
+  feed
+where
+  is_enabled = 1
+  and last_error is not null;
+
+-- name: CountDisabledFeeds :one
+select
+  count(*)
+from
+  feed
+where
+  is_enabled = 0;
+
+-- name: GetFeedByURL :one
+select
+  *
+from
+  feed
+where
+  url = ?;
+
+-- name: FindFeeds :many
+select
+  *
+from
+  feed
+where
+  url = sqlc.arg('target')
+  or name = sqlc.arg('target');
+
 -- name: CreateFeed :exec
 insert into
-  feed (name, url, feed_type)
+  feed (
+    name,
+    url,
+    feed_type,
+    auth_user,
+    auth_pass,
+    auth_header,
+    refresh_interval_seconds,
+    store_since,
+    date_format,
+    date_format_user_set,
+    custom_selectors
+  )
 values
-  (?, ?, ?);
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: UpdateFeedDate :exec
 update feed
@@ -24,28 +78,188 @@ set
 where
   id = ?;
 
+-- name: UpdateFeedType :exec
+update feed
+set
+  feed_type = ?
+where
+  id = ?;
+
+-- name: SetFeedCustomSelectors :exec
+update feed
+set
+  feed_type = 'custom',
+  custom_selectors = ?
+where
+  id = ?;
+
+-- name: UpdateFeedTitle :exec
+update feed
+set
+  name = ?,
+  title = ?,
+  description = ?
+where
+  id = ?;
+
+-- name: UpdateFeedCacheHeaders :exec
+update feed
+set
+  etag = ?,
+  last_modified = ?
+where
+  id = ?;
+
+-- name: UpdateFeedCheckedAt :exec
+update feed
+set
+  last_checked_at = ?
+where
+  id = ?;
+
+-- name: SetFeedEnabled :exec
+update feed
+set
+  is_enabled = ?
+where
+  id = ?;
+
+-- name: SetFeedRefreshInterval :exec
+update feed
+set
+  refresh_interval_seconds = ?
+where
+  id = ?;
+
+-- name: UpdateFeedSuggestedInterval :exec
+update feed
+set
+  suggested_interval_seconds = ?
+where
+  id = ?;
+
+-- name: UpdateFeedError :exec
+update feed
+set
+  last_error = ?,
+  last_error_at = ?
+where
+  id = ?;
+
+-- name: ClearFeedError :exec
+update feed
+set
+  last_error = null,
+  last_error_at = null
+where
+  id = ?;
+
 -- name: DeleteFeed :exec
 delete from feed
 where
   id = ?;
 
+-- name: DeletePostsByFeed :execrows
+delete from post
+where
+  feed_id = ?;
+
 -- name: ListPost :many
 select
   *
 from
   post;
 
--- name: CreatePost :exec
-insert
-or ignore into post (title, url, published_at, feed_id)
+-- name: CreatePost :execrows
+insert into
+  post (
+    title,
+    url,
+    published_at,
+    feed_id,
+    guid,
+    summary,
+    author,
+    categories,
+    date_estimated,
+    enclosure_url,
+    enclosure_type,
+    enclosure_length,
+    enclosure_duration,
+    image_url,
+    content,
+    is_archived,
+    created_at
+  )
 values
-  (?, ?, ?, ?);
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+on conflict (feed_id, guid) do nothing;
+
+-- name: GetPostContent :one
+select
+  content
+from
+  post
+where
+  id = ?;
 
 -- name: DeletePost :exec
 delete from post
 where
   id = ?;
 
+-- name: CreateDeletedPost :exec
+insert into
+  deleted_post (feed_id, guid)
+select
+  feed_id,
+  guid
+from
+  post
+where
+  id = ?
+on conflict do nothing;
+
+-- name: ListDeletedGUIDs :many
+select
+  guid
+from
+  deleted_post
+where
+  feed_id = ?;
+
+-- name: DeleteDeletedPost :exec
+delete from deleted_post
+where
+  feed_id = ?
+  and guid = ?;
+
+-- name: DeleteDeletedPostsByFeed :exec
+delete from deleted_post
+where
+  feed_id = ?;
+
+-- name: CreatePrunedDeletedPosts :exec
+insert into
+  deleted_post (feed_id, guid)
+select
+  feed_id,
+  guid
+from
+  post
+where
+  is_archived = 1
+  AND coalesce(is_starred, 0) = 0
+  AND julianday(published_at) < julianday(sqlc.arg('before'))
+on conflict do nothing;
+
+-- name: PrunePosts :execrows
+delete from post
+where
+  is_archived = 1
+  AND coalesce(is_starred, 0) = 0
+  AND julianday(published_at) < julianday(sqlc.arg('before'));
+
 -- name: ListPostsWithFeedFiltered :many
 select
   p.id,
@@ -55,15 +269,167 @@ select
   p.feed_id,
   p.is_archived,
   p.is_starred,
-  f.name as feed_name
+  p.summary,
+  p.is_read,
+  p.author,
+  p.categories,
+  p.date_estimated,
+  p.enclosure_url,
+  p.enclosure_type,
+  p.enclosure_length,
+  p.enclosure_duration,
+  p.image_url,
+  f.name as feed_name,
+  f.last_error as feed_error
 from
   post p
   inner join feed f on p.feed_id = f.id
 where
   (sqlc.narg('is_archived') IS NULL OR p.is_archived = sqlc.narg('is_archived'))
   AND (sqlc.narg('is_starred') IS NULL OR p.is_starred = sqlc.narg('is_starred'))
+  AND (sqlc.narg('feed_id') IS NULL OR p.feed_id = sqlc.narg('feed_id'))
+  AND (
+    sqlc.narg('category') IS NULL
+    OR instr(',' || p.categories || ',', ',' || sqlc.narg('category') || ',') > 0
+  )
+  AND (
+    sqlc.narg('since') IS NULL
+    OR julianday(coalesce(p.created_at, p.published_at)) > julianday(sqlc.narg('since'))
+  )
+  AND (
+    sqlc.narg('awake_at') IS NULL
+    OR p.snooze_until IS NULL
+    OR julianday(p.snooze_until) <= julianday(sqlc.narg('awake_at'))
+  )
+order by
+  case when sqlc.arg('sort') = 'feed' then f.name end collate nocase asc,
+  case when sqlc.arg('sort') = 'oldest' then p.published_at end asc,
+  case when sqlc.arg('sort') = 'first-seen' then p.created_at end desc,
+  p.published_at desc,
+  -- A stable order keeps pages from overlapping when dates tie
+  p.id desc
+limit
+  sqlc.arg('limit')
+offset
+  sqlc.arg('offset');
+
+-- name: SearchPosts :many
+select
+  p.id,
+  p.title,
+  p.url,
+  p.published_at,
+  p.feed_id,
+  p.is_archived,
+  p.is_starred,
+  p.summary,
+  p.is_read,
+  p.author,
+  p.categories,
+  p.date_estimated,
+  p.enclosure_url,
+  p.enclosure_type,
+  p.enclosure_length,
+  p.enclosure_duration,
+  p.image_url,
+  f.name as feed_name,
+  f.last_error as feed_error
+from
+  post_search
+  inner join post p on p.id = post_search.rowid
+  inner join feed f on p.feed_id = f.id
+where
+  post_search match sqlc.arg('query')
 order by
-  p.published_at desc;
+  -- bm25 is negative with better matches lower, so older posts are pulled
+  -- towards zero and rank below equally good recent ones
+  bm25(post_search) / (
+    1 + coalesce(julianday('now') - julianday(p.published_at), 365) / 365
+  )
+limit
+  200;
+
+-- name: UnreadCount :one
+select
+  count(*) as unread,
+  count(distinct feed_id) as feeds
+from
+  post
+where
+  is_read = 0
+  AND is_archived = 0;
+
+-- name: PostStats :one
+select
+  count(*) as total,
+  cast(coalesce(sum(is_read = 0 AND is_archived = 0), 0) as integer) as unread,
+  cast(coalesce(sum(is_starred = 1), 0) as integer) as starred,
+  cast(coalesce(sum(is_archived = 1), 0) as integer) as archived,
+  cast(coalesce(min(published_at), '') as text) as oldest,
+  cast(coalesce(max(published_at), '') as text) as newest
+from
+  post;
+
+-- name: FeedStats :one
+select
+  count(*) as total,
+  cast(coalesce(sum(is_enabled = 1), 0) as integer) as enabled,
+  cast(coalesce(sum(last_error is not null), 0) as integer) as failing
+from
+  feed;
+
+-- name: TopFeedsByPosts :many
+select
+  feed.name,
+  count(post.id) as posts
+from
+  feed
+  join post on post.feed_id = feed.id
+group by
+  feed.id
+order by
+  posts desc,
+  feed.name
+limit
+  ?;
+
+-- name: CountScreens :one
+select
+  (
+    select
+      count(*)
+    from
+      post
+    where
+      is_archived = 0
+      AND is_starred = 0
+      AND (
+        snooze_until IS NULL
+        OR julianday(snooze_until) <= julianday(sqlc.arg('awake_at'))
+      )
+  ) as inbox,
+  (
+    select
+      count(*)
+    from
+      post
+    where
+      is_starred = 1
+  ) as starred,
+  (
+    select
+      count(*)
+    from
+      post
+    where
+      is_archived = 1
+  ) as archive,
+  (
+    select
+      count(*)
+    from
+      feed
+  ) as feeds;
 
 -- name: ArchivePost :exec
 update post
@@ -72,6 +438,20 @@ set
 where
   id = ?;
 
+-- name: ArchiveAllInbox :execrows
+update post
+set
+  is_archived = 1
+where
+  is_archived = 0
+  AND is_starred = 0
+  AND (sqlc.narg('feed_id') IS NULL OR feed_id = sqlc.narg('feed_id'))
+  AND (
+    sqlc.narg('category') IS NULL
+    OR instr(',' || categories || ',', ',' || sqlc.narg('category') || ',') > 0
+  )
+  AND (snooze_until IS NULL OR julianday(snooze_until) <= julianday(sqlc.arg('now')));
+
 -- name: UnarchivePost :exec
 update post
 set
@@ -79,6 +459,13 @@ set
 where
   id = ?;
 
+-- name: SnoozePost :exec
+update post
+set
+  snooze_until = ?
+where
+  id = ?;
+
 -- name: StarPost :exec
 update post
 set
@@ -92,3 +479,73 @@ set
   is_starred = 0
 where
   id = ?;
+
+-- name: MarkRead :exec
+update post
+set
+  is_read = 1
+where
+  id = ?;
+
+-- name: MarkUnread :exec
+update post
+set
+  is_read = 0
+where
+  id = ?;
+
+-- name: GetSetting :one
+select
+  value
+from
+  setting
+where
+  key = ?;
+
+-- name: SetSetting :exec
+insert into
+  setting (key, value)
+values
+  (?, ?)
+on conflict (key) do update
+set
+  value = excluded.value;
+
+-- name: CreateMute :exec
+insert into
+  mute (pattern, feed_id)
+values
+  (?, ?);
+
+-- name: ListMutes :many
+select
+  mute.id,
+  mute.pattern,
+  mute.feed_id,
+  feed.name as feed_name
+from
+  mute
+  left join feed on feed.id = mute.feed_id
+order by
+  mute.id;
+
+-- name: ListFeedMutes :many
+select
+  pattern
+from
+  mute
+where
+  feed_id is null
+  or feed_id = ?
+order by
+  id;
+
+-- name: DeleteMute :execrows
+delete from mute
+where
+  id = ?;
+
+-- name: DeleteMutesByFeed :exec
+delete from mute
+where
+  feed_id = ?;
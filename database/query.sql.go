@@ -10,6 +10,35 @@ import (
 	"database/sql"
 )
 
+const archiveAllInbox = `-- name: ArchiveAllInbox :execrows
+update post
+set
+  is_archived = 1
+where
+  is_archived = 0
+  AND is_starred = 0
+  AND (?1 IS NULL OR feed_id = ?1)
+  AND (
+    ?2 IS NULL
+    OR instr(',' || categories || ',', ',' || ?2 || ',') > 0
+  )
+  AND (snooze_until IS NULL OR julianday(snooze_until) <= julianday(?3))
+`
+
+type ArchiveAllInboxParams struct {
+	FeedID   interface{}
+	Category interface{}
+	Now      interface{}
+}
+
+func (q *Queries) ArchiveAllInbox(ctx context.Context, arg ArchiveAllInboxParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, archiveAllInbox, arg.FeedID, arg.Category, arg.Now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const archivePost = `-- name: ArchivePost :exec
 update post
 set
@@ -23,45 +52,294 @@ func (q *Queries) ArchivePost(ctx context.Context, id int64) error {
 	return err
 }
 
+const clearFeedError = `-- name: ClearFeedError :exec
+update feed
+set
+  last_error = null,
+  last_error_at = null
+where
+  id = ?
+`
+
+func (q *Queries) ClearFeedError(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, clearFeedError, id)
+	return err
+}
+
+const countDisabledFeeds = `-- name: CountDisabledFeeds :one
+select
+  count(*)
+from
+  feed
+where
+  is_enabled = 0
+`
+
+func (q *Queries) CountDisabledFeeds(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countDisabledFeeds)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countScreens = `-- name: CountScreens :one
+select
+  (
+    select
+      count(*)
+    from
+      post
+    where
+      is_archived = 0
+      AND is_starred = 0
+      AND (
+        snooze_until IS NULL
+        OR julianday(snooze_until) <= julianday(?)
+      )
+  ) as inbox,
+  (
+    select
+      count(*)
+    from
+      post
+    where
+      is_starred = 1
+  ) as starred,
+  (
+    select
+      count(*)
+    from
+      post
+    where
+      is_archived = 1
+  ) as archive,
+  (
+    select
+      count(*)
+    from
+      feed
+  ) as feeds
+`
+
+type CountScreensRow struct {
+	Inbox   int64
+	Starred int64
+	Archive int64
+	Feeds   int64
+}
+
+func (q *Queries) CountScreens(ctx context.Context, awakeAt interface{}) (CountScreensRow, error) {
+	row := q.db.QueryRowContext(ctx, countScreens, awakeAt)
+	var i CountScreensRow
+	err := row.Scan(
+		&i.Inbox,
+		&i.Starred,
+		&i.Archive,
+		&i.Feeds,
+	)
+	return i, err
+}
+
+const createDeletedPost = `-- name: CreateDeletedPost :exec
+insert into
+  deleted_post (feed_id, guid)
+select
+  feed_id,
+  guid
+from
+  post
+where
+  id = ?
+on conflict do nothing
+`
+
+func (q *Queries) CreateDeletedPost(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, createDeletedPost, id)
+	return err
+}
+
 const createFeed = `-- name: CreateFeed :exec
 insert into
-  feed (name, url, feed_type)
+  feed (
+    name,
+    url,
+    feed_type,
+    auth_user,
+    auth_pass,
+    auth_header,
+    refresh_interval_seconds,
+    store_since,
+    date_format,
+    date_format_user_set,
+    custom_selectors
+  )
 values
-  (?, ?, ?)
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type CreateFeedParams struct {
-	Name     string
-	Url      string
-	FeedType string
+	Name                   string
+	Url                    string
+	FeedType               string
+	AuthUser               sql.NullString
+	AuthPass               sql.NullString
+	AuthHeader             sql.NullString
+	RefreshIntervalSeconds sql.NullInt64
+	StoreSince             sql.NullString
+	DateFormat             sql.NullString
+	DateFormatUserSet      sql.NullInt64
+	CustomSelectors        sql.NullString
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) error {
-	_, err := q.db.ExecContext(ctx, createFeed, arg.Name, arg.Url, arg.FeedType)
+	_, err := q.db.ExecContext(ctx, createFeed,
+		arg.Name,
+		arg.Url,
+		arg.FeedType,
+		arg.AuthUser,
+		arg.AuthPass,
+		arg.AuthHeader,
+		arg.RefreshIntervalSeconds,
+		arg.StoreSince,
+		arg.DateFormat,
+		arg.DateFormatUserSet,
+		arg.CustomSelectors,
+	)
 	return err
 }
 
-const createPost = `-- name: CreatePost :exec
-insert
-or ignore into post (title, url, published_at, feed_id)
+const createMute = `-- name: CreateMute :exec
+insert into
+  mute (pattern, feed_id)
 values
-  (?, ?, ?, ?)
+  (?, ?)
+`
+
+type CreateMuteParams struct {
+	Pattern string
+	FeedID  sql.NullInt64
+}
+
+func (q *Queries) CreateMute(ctx context.Context, arg CreateMuteParams) error {
+	_, err := q.db.ExecContext(ctx, createMute, arg.Pattern, arg.FeedID)
+	return err
+}
+
+const createPost = `-- name: CreatePost :execrows
+insert into
+  post (
+    title,
+    url,
+    published_at,
+    feed_id,
+    guid,
+    summary,
+    author,
+    categories,
+    date_estimated,
+    enclosure_url,
+    enclosure_type,
+    enclosure_length,
+    enclosure_duration,
+    image_url,
+    content,
+    is_archived,
+    created_at
+  )
+values
+  (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, strftime('%Y-%m-%dT%H:%M:%SZ', 'now'))
+on conflict (feed_id, guid) do nothing
 `
 
 type CreatePostParams struct {
-	Title       string
-	Url         string
-	PublishedAt string
-	FeedID      int64
+	Title             string
+	Url               string
+	PublishedAt       string
+	FeedID            int64
+	Guid              string
+	Summary           sql.NullString
+	Author            sql.NullString
+	Categories        sql.NullString
+	DateEstimated     sql.NullInt64
+	EnclosureUrl      sql.NullString
+	EnclosureType     sql.NullString
+	EnclosureLength   sql.NullInt64
+	EnclosureDuration sql.NullInt64
+	ImageUrl          sql.NullString
+	Content           sql.NullString
+	IsArchived        sql.NullInt64
 }
 
-func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) error {
-	_, err := q.db.ExecContext(ctx, createPost,
+func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createPost,
 		arg.Title,
 		arg.Url,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Guid,
+		arg.Summary,
+		arg.Author,
+		arg.Categories,
+		arg.DateEstimated,
+		arg.EnclosureUrl,
+		arg.EnclosureType,
+		arg.EnclosureLength,
+		arg.EnclosureDuration,
+		arg.ImageUrl,
+		arg.Content,
+		arg.IsArchived,
 	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createPrunedDeletedPosts = `-- name: CreatePrunedDeletedPosts :exec
+insert into
+  deleted_post (feed_id, guid)
+select
+  feed_id,
+  guid
+from
+  post
+where
+  is_archived = 1
+  AND coalesce(is_starred, 0) = 0
+  AND julianday(published_at) < julianday(?)
+on conflict do nothing
+`
+
+func (q *Queries) CreatePrunedDeletedPosts(ctx context.Context, before interface{}) error {
+	_, err := q.db.ExecContext(ctx, createPrunedDeletedPosts, before)
+	return err
+}
+
+const deleteDeletedPost = `-- name: DeleteDeletedPost :exec
+delete from deleted_post
+where
+  feed_id = ?
+  and guid = ?
+`
+
+type DeleteDeletedPostParams struct {
+	FeedID int64
+	Guid   string
+}
+
+func (q *Queries) DeleteDeletedPost(ctx context.Context, arg DeleteDeletedPostParams) error {
+	_, err := q.db.ExecContext(ctx, deleteDeletedPost, arg.FeedID, arg.Guid)
+	return err
+}
+
+const deleteDeletedPostsByFeed = `-- name: DeleteDeletedPostsByFeed :exec
+delete from deleted_post
+where
+  feed_id = ?
+`
+
+func (q *Queries) DeleteDeletedPostsByFeed(ctx context.Context, feedID int64) error {
+	_, err := q.db.ExecContext(ctx, deleteDeletedPostsByFeed, feedID)
 	return err
 }
 
@@ -76,6 +354,31 @@ func (q *Queries) DeleteFeed(ctx context.Context, id int64) error {
 	return err
 }
 
+const deleteMute = `-- name: DeleteMute :execrows
+delete from mute
+where
+  id = ?
+`
+
+func (q *Queries) DeleteMute(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMute, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteMutesByFeed = `-- name: DeleteMutesByFeed :exec
+delete from mute
+where
+  feed_id = ?
+`
+
+func (q *Queries) DeleteMutesByFeed(ctx context.Context, feedID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, deleteMutesByFeed, feedID)
+	return err
+}
+
 const deletePost = `-- name: DeletePost :exec
 delete from post
 where
@@ -87,9 +390,350 @@ func (q *Queries) DeletePost(ctx context.Context, id int64) error {
 	return err
 }
 
+const deletePostsByFeed = `-- name: DeletePostsByFeed :execrows
+delete from post
+where
+  feed_id = ?
+`
+
+func (q *Queries) DeletePostsByFeed(ctx context.Context, feedID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePostsByFeed, feedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const feedStats = `-- name: FeedStats :one
+select
+  count(*) as total,
+  cast(coalesce(sum(is_enabled = 1), 0) as integer) as enabled,
+  cast(coalesce(sum(last_error is not null), 0) as integer) as failing
+from
+  feed
+`
+
+type FeedStatsRow struct {
+	Total   int64
+	Enabled int64
+	Failing int64
+}
+
+func (q *Queries) FeedStats(ctx context.Context) (FeedStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, feedStats)
+	var i FeedStatsRow
+	err := row.Scan(&i.Total, &i.Enabled, &i.Failing)
+	return i, err
+}
+
+const findFeeds = `-- name: FindFeeds :many
+select
+  id, name, last_updated_at, url, feed_type, date_format, etag, last_modified, last_checked_at, custom_selectors, last_error, last_error_at, is_enabled, auth_user, auth_pass, auth_header, refresh_interval_seconds, suggested_interval_seconds, store_since, title, description, date_format_user_set
+from
+  feed
+where
+  url = ?1
+  or name = ?1
+`
+
+func (q *Queries) FindFeeds(ctx context.Context, target string) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, findFeeds, target)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.LastUpdatedAt,
+			&i.Url,
+			&i.FeedType,
+			&i.DateFormat,
+			&i.Etag,
+			&i.LastModified,
+			&i.LastCheckedAt,
+			&i.CustomSelectors,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.IsEnabled,
+			&i.AuthUser,
+			&i.AuthPass,
+			&i.AuthHeader,
+			&i.RefreshIntervalSeconds,
+			&i.SuggestedIntervalSeconds,
+			&i.StoreSince,
+			&i.Title,
+			&i.Description,
+			&i.DateFormatUserSet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFeedByURL = `-- name: GetFeedByURL :one
+select
+  id, name, last_updated_at, url, feed_type, date_format, etag, last_modified, last_checked_at, custom_selectors, last_error, last_error_at, is_enabled, auth_user, auth_pass, auth_header, refresh_interval_seconds, suggested_interval_seconds, store_since, title, description, date_format_user_set
+from
+  feed
+where
+  url = ?
+`
+
+func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, getFeedByURL, url)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.LastUpdatedAt,
+		&i.Url,
+		&i.FeedType,
+		&i.DateFormat,
+		&i.Etag,
+		&i.LastModified,
+		&i.LastCheckedAt,
+		&i.CustomSelectors,
+		&i.LastError,
+		&i.LastErrorAt,
+		&i.IsEnabled,
+		&i.AuthUser,
+		&i.AuthPass,
+		&i.AuthHeader,
+		&i.RefreshIntervalSeconds,
+		&i.SuggestedIntervalSeconds,
+		&i.StoreSince,
+		&i.Title,
+		&i.Description,
+		&i.DateFormatUserSet,
+	)
+	return i, err
+}
+
+const getPostContent = `-- name: GetPostContent :one
+select
+  content
+from
+  post
+where
+  id = ?
+`
+
+func (q *Queries) GetPostContent(ctx context.Context, id int64) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, getPostContent, id)
+	var content sql.NullString
+	err := row.Scan(&content)
+	return content, err
+}
+
+const getSetting = `-- name: GetSetting :one
+select
+  value
+from
+  setting
+where
+  key = ?
+`
+
+func (q *Queries) GetSetting(ctx context.Context, key string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getSetting, key)
+	var value string
+	err := row.Scan(&value)
+	return value, err
+}
+
+const listDeletedGUIDs = `-- name: ListDeletedGUIDs :many
+select
+  guid
+from
+  deleted_post
+where
+  feed_id = ?
+`
+
+func (q *Queries) ListDeletedGUIDs(ctx context.Context, feedID int64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listDeletedGUIDs, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var guid string
+		if err := rows.Scan(&guid); err != nil {
+			return nil, err
+		}
+		items = append(items, guid)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEnabledFeeds = `-- name: ListEnabledFeeds :many
+select
+  id, name, last_updated_at, url, feed_type, date_format, etag, last_modified, last_checked_at, custom_selectors, last_error, last_error_at, is_enabled, auth_user, auth_pass, auth_header, refresh_interval_seconds, suggested_interval_seconds, store_since, title, description, date_format_user_set
+from
+  feed
+where
+  is_enabled = 1
+`
+
+func (q *Queries) ListEnabledFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, listEnabledFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.LastUpdatedAt,
+			&i.Url,
+			&i.FeedType,
+			&i.DateFormat,
+			&i.Etag,
+			&i.LastModified,
+			&i.LastCheckedAt,
+			&i.CustomSelectors,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.IsEnabled,
+			&i.AuthUser,
+			&i.AuthPass,
+			&i.AuthHeader,
+			&i.RefreshIntervalSeconds,
+			&i.SuggestedIntervalSeconds,
+			&i.StoreSince,
+			&i.Title,
+			&i.Description,
+			&i.DateFormatUserSet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFailedFeeds = `-- name: ListFailedFeeds :many
+select
+  id, name, last_updated_at, url, feed_type, date_format, etag, last_modified, last_checked_at, custom_selectors, last_error, last_error_at, is_enabled, auth_user, auth_pass, auth_header, refresh_interval_seconds, suggested_interval_seconds, store_since, title, description, date_format_user_set
+from
+  feed
+where
+  is_enabled = 1
+  and last_error is not null
+`
+
+func (q *Queries) ListFailedFeeds(ctx context.Context) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, listFailedFeeds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.LastUpdatedAt,
+			&i.Url,
+			&i.FeedType,
+			&i.DateFormat,
+			&i.Etag,
+			&i.LastModified,
+			&i.LastCheckedAt,
+			&i.CustomSelectors,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.IsEnabled,
+			&i.AuthUser,
+			&i.AuthPass,
+			&i.AuthHeader,
+			&i.RefreshIntervalSeconds,
+			&i.SuggestedIntervalSeconds,
+			&i.StoreSince,
+			&i.Title,
+			&i.Description,
+			&i.DateFormatUserSet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFeedMutes = `-- name: ListFeedMutes :many
+select
+  pattern
+from
+  mute
+where
+  feed_id is null
+  or feed_id = ?
+order by
+  id
+`
+
+func (q *Queries) ListFeedMutes(ctx context.Context, feedID sql.NullInt64) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listFeedMutes, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var pattern string
+		if err := rows.Scan(&pattern); err != nil {
+			return nil, err
+		}
+		items = append(items, pattern)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFeeds = `-- name: ListFeeds :many
 select
-  id, name, last_updated_at, url, feed_type, date_format
+  id, name, last_updated_at, url, feed_type, date_format, etag, last_modified, last_checked_at, custom_selectors, last_error, last_error_at, is_enabled, auth_user, auth_pass, auth_header, refresh_interval_seconds, suggested_interval_seconds, store_since, title, description, date_format_user_set
 from
   feed
 `
@@ -100,16 +744,80 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]Feed, error) {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Feed
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.LastUpdatedAt,
+			&i.Url,
+			&i.FeedType,
+			&i.DateFormat,
+			&i.Etag,
+			&i.LastModified,
+			&i.LastCheckedAt,
+			&i.CustomSelectors,
+			&i.LastError,
+			&i.LastErrorAt,
+			&i.IsEnabled,
+			&i.AuthUser,
+			&i.AuthPass,
+			&i.AuthHeader,
+			&i.RefreshIntervalSeconds,
+			&i.SuggestedIntervalSeconds,
+			&i.StoreSince,
+			&i.Title,
+			&i.Description,
+			&i.DateFormatUserSet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMutes = `-- name: ListMutes :many
+select
+  mute.id,
+  mute.pattern,
+  mute.feed_id,
+  feed.name as feed_name
+from
+  mute
+  left join feed on feed.id = mute.feed_id
+order by
+  mute.id
+`
+
+type ListMutesRow struct {
+	ID       int64
+	Pattern  string
+	FeedID   sql.NullInt64
+	FeedName sql.NullString
+}
+
+func (q *Queries) ListMutes(ctx context.Context) ([]ListMutesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listMutes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMutesRow
 	for rows.Next() {
-		var i Feed
+		var i ListMutesRow
 		if err := rows.Scan(
 			&i.ID,
-			&i.Name,
-			&i.LastUpdatedAt,
-			&i.Url,
-			&i.FeedType,
-			&i.DateFormat,
+			&i.Pattern,
+			&i.FeedID,
+			&i.FeedName,
 		); err != nil {
 			return nil, err
 		}
@@ -126,7 +834,7 @@ func (q *Queries) ListFeeds(ctx context.Context) ([]Feed, error) {
 
 const listPost = `-- name: ListPost :many
 select
-  id, title, url, published_at, feed_id, is_archived, is_starred
+  id, title, url, published_at, feed_id, is_archived, is_starred, guid, summary, is_read, author, categories, date_estimated, created_at, snooze_until, enclosure_url, enclosure_type, enclosure_length, enclosure_duration, image_url, content
 from
   post
 `
@@ -148,6 +856,20 @@ func (q *Queries) ListPost(ctx context.Context) ([]Post, error) {
 			&i.FeedID,
 			&i.IsArchived,
 			&i.IsStarred,
+			&i.Guid,
+			&i.Summary,
+			&i.IsRead,
+			&i.Author,
+			&i.Categories,
+			&i.DateEstimated,
+			&i.CreatedAt,
+			&i.SnoozeUntil,
+			&i.EnclosureUrl,
+			&i.EnclosureType,
+			&i.EnclosureLength,
+			&i.EnclosureDuration,
+			&i.ImageUrl,
+			&i.Content,
 		); err != nil {
 			return nil, err
 		}
@@ -171,35 +893,97 @@ select
   p.feed_id,
   p.is_archived,
   p.is_starred,
-  f.name as feed_name
+  p.summary,
+  p.is_read,
+  p.author,
+  p.categories,
+  p.date_estimated,
+  p.enclosure_url,
+  p.enclosure_type,
+  p.enclosure_length,
+  p.enclosure_duration,
+  p.image_url,
+  f.name as feed_name,
+  f.last_error as feed_error
 from
   post p
   inner join feed f on p.feed_id = f.id
 where
   (?1 IS NULL OR p.is_archived = ?1)
   AND (?2 IS NULL OR p.is_starred = ?2)
+  AND (?3 IS NULL OR p.feed_id = ?3)
+  AND (
+    ?4 IS NULL
+    OR instr(',' || p.categories || ',', ',' || ?4 || ',') > 0
+  )
+  AND (
+    ?5 IS NULL
+    OR julianday(coalesce(p.created_at, p.published_at)) > julianday(?5)
+  )
+  AND (
+    ?6 IS NULL
+    OR p.snooze_until IS NULL
+    OR julianday(p.snooze_until) <= julianday(?6)
+  )
 order by
-  p.published_at desc
+  case when ?7 = 'feed' then f.name end collate nocase asc,
+  case when ?7 = 'oldest' then p.published_at end asc,
+  case when ?7 = 'first-seen' then p.created_at end desc,
+  p.published_at desc,
+  -- A stable order keeps pages from overlapping when dates tie
+  p.id desc
+limit
+  ?8
+offset
+  ?9
 `
 
 type ListPostsWithFeedFilteredParams struct {
 	IsArchived interface{}
 	IsStarred  interface{}
+	FeedID     interface{}
+	Category   interface{}
+	Since      interface{}
+	AwakeAt    interface{}
+	Sort       interface{}
+	Limit      int64
+	Offset     int64
 }
 
 type ListPostsWithFeedFilteredRow struct {
-	ID          int64
-	Title       string
-	Url         string
-	PublishedAt string
-	FeedID      int64
-	IsArchived  sql.NullInt64
-	IsStarred   sql.NullInt64
-	FeedName    string
+	ID                int64
+	Title             string
+	Url               string
+	PublishedAt       string
+	FeedID            int64
+	IsArchived        sql.NullInt64
+	IsStarred         sql.NullInt64
+	Summary           sql.NullString
+	IsRead            sql.NullInt64
+	Author            sql.NullString
+	Categories        sql.NullString
+	DateEstimated     sql.NullInt64
+	EnclosureUrl      sql.NullString
+	EnclosureType     sql.NullString
+	EnclosureLength   sql.NullInt64
+	EnclosureDuration sql.NullInt64
+	ImageUrl          sql.NullString
+	FeedName          string
+	FeedError         sql.NullString
 }
 
 func (q *Queries) ListPostsWithFeedFiltered(ctx context.Context, arg ListPostsWithFeedFilteredParams) ([]ListPostsWithFeedFilteredRow, error) {
-	rows, err := q.db.QueryContext(ctx, listPostsWithFeedFiltered, arg.IsArchived, arg.IsStarred)
+	rows, err := q.db.QueryContext(ctx, listPostsWithFeedFiltered,
+		arg.IsArchived,
+		arg.IsStarred,
+		arg.FeedID,
+		arg.Category,
+		arg.Since,
+		arg.AwakeAt,
+		arg.Sort,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +999,197 @@ func (q *Queries) ListPostsWithFeedFiltered(ctx context.Context, arg ListPostsWi
 			&i.FeedID,
 			&i.IsArchived,
 			&i.IsStarred,
+			&i.Summary,
+			&i.IsRead,
+			&i.Author,
+			&i.Categories,
+			&i.DateEstimated,
+			&i.EnclosureUrl,
+			&i.EnclosureType,
+			&i.EnclosureLength,
+			&i.EnclosureDuration,
+			&i.ImageUrl,
+			&i.FeedName,
+			&i.FeedError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markRead = `-- name: MarkRead :exec
+update post
+set
+  is_read = 1
+where
+  id = ?
+`
+
+func (q *Queries) MarkRead(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markRead, id)
+	return err
+}
+
+const markUnread = `-- name: MarkUnread :exec
+update post
+set
+  is_read = 0
+where
+  id = ?
+`
+
+func (q *Queries) MarkUnread(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, markUnread, id)
+	return err
+}
+
+const postStats = `-- name: PostStats :one
+select
+  count(*) as total,
+  cast(coalesce(sum(is_read = 0 AND is_archived = 0), 0) as integer) as unread,
+  cast(coalesce(sum(is_starred = 1), 0) as integer) as starred,
+  cast(coalesce(sum(is_archived = 1), 0) as integer) as archived,
+  cast(coalesce(min(published_at), '') as text) as oldest,
+  cast(coalesce(max(published_at), '') as text) as newest
+from
+  post
+`
+
+type PostStatsRow struct {
+	Total    int64
+	Unread   int64
+	Starred  int64
+	Archived int64
+	Oldest   string
+	Newest   string
+}
+
+func (q *Queries) PostStats(ctx context.Context) (PostStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, postStats)
+	var i PostStatsRow
+	err := row.Scan(
+		&i.Total,
+		&i.Unread,
+		&i.Starred,
+		&i.Archived,
+		&i.Oldest,
+		&i.Newest,
+	)
+	return i, err
+}
+
+const prunePosts = `-- name: PrunePosts :execrows
+delete from post
+where
+  is_archived = 1
+  AND coalesce(is_starred, 0) = 0
+  AND julianday(published_at) < julianday(?)
+`
+
+func (q *Queries) PrunePosts(ctx context.Context, before interface{}) (int64, error) {
+	result, err := q.db.ExecContext(ctx, prunePosts, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const searchPosts = `-- name: SearchPosts :many
+select
+  p.id,
+  p.title,
+  p.url,
+  p.published_at,
+  p.feed_id,
+  p.is_archived,
+  p.is_starred,
+  p.summary,
+  p.is_read,
+  p.author,
+  p.categories,
+  p.date_estimated,
+  p.enclosure_url,
+  p.enclosure_type,
+  p.enclosure_length,
+  p.enclosure_duration,
+  p.image_url,
+  f.name as feed_name,
+  f.last_error as feed_error
+from
+  post_search
+  inner join post p on p.id = post_search.rowid
+  inner join feed f on p.feed_id = f.id
+where
+  post_search match ?
+order by
+  -- bm25 is negative with better matches lower, so older posts are pulled
+  -- towards zero and rank below equally good recent ones
+  bm25(post_search) / (
+    1 + coalesce(julianday('now') - julianday(p.published_at), 365) / 365
+  )
+limit
+  200
+`
+
+type SearchPostsRow struct {
+	ID                int64
+	Title             string
+	Url               string
+	PublishedAt       string
+	FeedID            int64
+	IsArchived        sql.NullInt64
+	IsStarred         sql.NullInt64
+	Summary           sql.NullString
+	IsRead            sql.NullInt64
+	Author            sql.NullString
+	Categories        sql.NullString
+	DateEstimated     sql.NullInt64
+	EnclosureUrl      sql.NullString
+	EnclosureType     sql.NullString
+	EnclosureLength   sql.NullInt64
+	EnclosureDuration sql.NullInt64
+	ImageUrl          sql.NullString
+	FeedName          string
+	FeedError         sql.NullString
+}
+
+func (q *Queries) SearchPosts(ctx context.Context, query string) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Url,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.IsArchived,
+			&i.IsStarred,
+			&i.Summary,
+			&i.IsRead,
+			&i.Author,
+			&i.Categories,
+			&i.DateEstimated,
+			&i.EnclosureUrl,
+			&i.EnclosureType,
+			&i.EnclosureLength,
+			&i.EnclosureDuration,
+			&i.ImageUrl,
 			&i.FeedName,
+			&i.FeedError,
 		); err != nil {
 			return nil, err
 		}
@@ -230,6 +1204,99 @@ func (q *Queries) ListPostsWithFeedFiltered(ctx context.Context, arg ListPostsWi
 	return items, nil
 }
 
+const setFeedCustomSelectors = `-- name: SetFeedCustomSelectors :exec
+update feed
+set
+  feed_type = 'custom',
+  custom_selectors = ?
+where
+  id = ?
+`
+
+type SetFeedCustomSelectorsParams struct {
+	CustomSelectors sql.NullString
+	ID              int64
+}
+
+func (q *Queries) SetFeedCustomSelectors(ctx context.Context, arg SetFeedCustomSelectorsParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedCustomSelectors, arg.CustomSelectors, arg.ID)
+	return err
+}
+
+const setFeedEnabled = `-- name: SetFeedEnabled :exec
+update feed
+set
+  is_enabled = ?
+where
+  id = ?
+`
+
+type SetFeedEnabledParams struct {
+	IsEnabled sql.NullInt64
+	ID        int64
+}
+
+func (q *Queries) SetFeedEnabled(ctx context.Context, arg SetFeedEnabledParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedEnabled, arg.IsEnabled, arg.ID)
+	return err
+}
+
+const setFeedRefreshInterval = `-- name: SetFeedRefreshInterval :exec
+update feed
+set
+  refresh_interval_seconds = ?
+where
+  id = ?
+`
+
+type SetFeedRefreshIntervalParams struct {
+	RefreshIntervalSeconds sql.NullInt64
+	ID                     int64
+}
+
+func (q *Queries) SetFeedRefreshInterval(ctx context.Context, arg SetFeedRefreshIntervalParams) error {
+	_, err := q.db.ExecContext(ctx, setFeedRefreshInterval, arg.RefreshIntervalSeconds, arg.ID)
+	return err
+}
+
+const setSetting = `-- name: SetSetting :exec
+insert into
+  setting (key, value)
+values
+  (?, ?)
+on conflict (key) do update
+set
+  value = excluded.value
+`
+
+type SetSettingParams struct {
+	Key   string
+	Value string
+}
+
+func (q *Queries) SetSetting(ctx context.Context, arg SetSettingParams) error {
+	_, err := q.db.ExecContext(ctx, setSetting, arg.Key, arg.Value)
+	return err
+}
+
+const snoozePost = `-- name: SnoozePost :exec
+update post
+set
+  snooze_until = ?
+where
+  id = ?
+`
+
+type SnoozePostParams struct {
+	SnoozeUntil sql.NullString
+	ID          int64
+}
+
+func (q *Queries) SnoozePost(ctx context.Context, arg SnoozePostParams) error {
+	_, err := q.db.ExecContext(ctx, snoozePost, arg.SnoozeUntil, arg.ID)
+	return err
+}
+
 const starPost = `-- name: StarPost :exec
 update post
 set
@@ -243,6 +1310,50 @@ func (q *Queries) StarPost(ctx context.Context, id int64) error {
 	return err
 }
 
+const topFeedsByPosts = `-- name: TopFeedsByPosts :many
+select
+  feed.name,
+  count(post.id) as posts
+from
+  feed
+  join post on post.feed_id = feed.id
+group by
+  feed.id
+order by
+  posts desc,
+  feed.name
+limit
+  ?
+`
+
+type TopFeedsByPostsRow struct {
+	Name  string
+	Posts int64
+}
+
+func (q *Queries) TopFeedsByPosts(ctx context.Context, limit int64) ([]TopFeedsByPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, topFeedsByPosts, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TopFeedsByPostsRow
+	for rows.Next() {
+		var i TopFeedsByPostsRow
+		if err := rows.Scan(&i.Name, &i.Posts); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const unarchivePost = `-- name: UnarchivePost :exec
 update post
 set
@@ -256,6 +1367,29 @@ func (q *Queries) UnarchivePost(ctx context.Context, id int64) error {
 	return err
 }
 
+const unreadCount = `-- name: UnreadCount :one
+select
+  count(*) as unread,
+  count(distinct feed_id) as feeds
+from
+  post
+where
+  is_read = 0
+  AND is_archived = 0
+`
+
+type UnreadCountRow struct {
+	Unread int64
+	Feeds  int64
+}
+
+func (q *Queries) UnreadCount(ctx context.Context) (UnreadCountRow, error) {
+	row := q.db.QueryRowContext(ctx, unreadCount)
+	var i UnreadCountRow
+	err := row.Scan(&i.Unread, &i.Feeds)
+	return i, err
+}
+
 const unstarPost = `-- name: UnstarPost :exec
 update post
 set
@@ -269,6 +1403,44 @@ func (q *Queries) UnstarPost(ctx context.Context, id int64) error {
 	return err
 }
 
+const updateFeedCacheHeaders = `-- name: UpdateFeedCacheHeaders :exec
+update feed
+set
+  etag = ?,
+  last_modified = ?
+where
+  id = ?
+`
+
+type UpdateFeedCacheHeadersParams struct {
+	Etag         sql.NullString
+	LastModified sql.NullString
+	ID           int64
+}
+
+func (q *Queries) UpdateFeedCacheHeaders(ctx context.Context, arg UpdateFeedCacheHeadersParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedCacheHeaders, arg.Etag, arg.LastModified, arg.ID)
+	return err
+}
+
+const updateFeedCheckedAt = `-- name: UpdateFeedCheckedAt :exec
+update feed
+set
+  last_checked_at = ?
+where
+  id = ?
+`
+
+type UpdateFeedCheckedAtParams struct {
+	LastCheckedAt sql.NullString
+	ID            int64
+}
+
+func (q *Queries) UpdateFeedCheckedAt(ctx context.Context, arg UpdateFeedCheckedAtParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedCheckedAt, arg.LastCheckedAt, arg.ID)
+	return err
+}
+
 const updateFeedDate = `-- name: UpdateFeedDate :exec
 update feed
 set
@@ -287,6 +1459,26 @@ func (q *Queries) UpdateFeedDate(ctx context.Context, arg UpdateFeedDateParams)
 	return err
 }
 
+const updateFeedError = `-- name: UpdateFeedError :exec
+update feed
+set
+  last_error = ?,
+  last_error_at = ?
+where
+  id = ?
+`
+
+type UpdateFeedErrorParams struct {
+	LastError   sql.NullString
+	LastErrorAt sql.NullString
+	ID          int64
+}
+
+func (q *Queries) UpdateFeedError(ctx context.Context, arg UpdateFeedErrorParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedError, arg.LastError, arg.LastErrorAt, arg.ID)
+	return err
+}
+
 const updateFeedFormat = `-- name: UpdateFeedFormat :exec
 update feed
 set
@@ -304,3 +1496,66 @@ func (q *Queries) UpdateFeedFormat(ctx context.Context, arg UpdateFeedFormatPara
 	_, err := q.db.ExecContext(ctx, updateFeedFormat, arg.DateFormat, arg.ID)
 	return err
 }
+
+const updateFeedSuggestedInterval = `-- name: UpdateFeedSuggestedInterval :exec
+update feed
+set
+  suggested_interval_seconds = ?
+where
+  id = ?
+`
+
+type UpdateFeedSuggestedIntervalParams struct {
+	SuggestedIntervalSeconds sql.NullInt64
+	ID                       int64
+}
+
+func (q *Queries) UpdateFeedSuggestedInterval(ctx context.Context, arg UpdateFeedSuggestedIntervalParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedSuggestedInterval, arg.SuggestedIntervalSeconds, arg.ID)
+	return err
+}
+
+const updateFeedTitle = `-- name: UpdateFeedTitle :exec
+update feed
+set
+  name = ?,
+  title = ?,
+  description = ?
+where
+  id = ?
+`
+
+type UpdateFeedTitleParams struct {
+	Name        string
+	Title       sql.NullString
+	Description sql.NullString
+	ID          int64
+}
+
+func (q *Queries) UpdateFeedTitle(ctx context.Context, arg UpdateFeedTitleParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedTitle,
+		arg.Name,
+		arg.Title,
+		arg.Description,
+		arg.ID,
+	)
+	return err
+}
+
+const updateFeedType = `-- name: UpdateFeedType :exec
+update feed
+set
+  feed_type = ?
+where
+  id = ?
+`
+
+type UpdateFeedTypeParams struct {
+	FeedType string
+	ID       int64
+}
+
+func (q *Queries) UpdateFeedType(ctx context.Context, arg UpdateFeedTypeParams) error {
+	_, err := q.db.ExecContext(ctx, updateFeedType, arg.FeedType, arg.ID)
+	return err
+}
@@ -1,10 +1,40 @@
+-- The latest schema, used as is for new databases. Every change here needs
+-- a matching file in migrations/ to upgrade existing databases.
+
 create table feed (
   id integer primary key,
   name text not null,
   last_updated_at text,
-  url text not null,
-  feed_type text check (feed_type in ('rss', 'atom', 'custom')) not null,
-  date_format text
+  url text not null unique,
+  feed_type text check (feed_type in ('rss', 'rdf', 'atom', 'json', 'custom')) not null,
+  date_format text,
+  etag text,
+  last_modified text,
+  last_checked_at text,
+  custom_selectors text,
+  last_error text,
+  last_error_at text,
+  is_enabled integer default 1,
+  -- Credentials are stored in plaintext; values may reference environment
+  -- variables as ${NAME} to keep secrets out of the database
+  auth_user text,
+  auth_pass text,
+  auth_header text,
+  -- Minimum time between fetches; null fetches on every run
+  refresh_interval_seconds integer,
+  -- How often the feed itself asks to be polled, from <ttl> or the
+  -- syndication module; refresh_interval_seconds takes precedence
+  suggested_interval_seconds integer,
+  -- Posts published before this time are skipped when storing, so adding
+  -- a feed with a long history doesn't flood the inbox
+  store_since text,
+  -- The feed's own title and description as of the last fetch; name
+  -- follows title unless it was set to something else
+  title text,
+  description text,
+  -- Set when date_format was given with add -date-format, so detection
+  -- leaves it alone
+  date_format_user_set integer default 0
 );
 
 create table post (
@@ -15,6 +45,82 @@ create table post (
   feed_id integer not null,
   is_archived integer default 0,
   is_starred integer default 0,
+  guid text not null,
+  summary text,
+  is_read integer default 0,
+  author text,
+  -- Comma-separated, commas within a category are replaced when parsing
+  categories text,
+  -- Set when the feed gave no usable date and published_at is a guess
+  date_estimated integer default 0,
+  -- When the post was first stored, unaffected by the feed's dates
+  created_at text,
+  -- Hidden from the inbox until this time
+  snooze_until text,
+  -- Attached media such as a podcast episode, length is in bytes
+  enclosure_url text,
+  enclosure_type text,
+  enclosure_length integer,
+  -- Running time of the enclosure in seconds, from itunes:duration
+  enclosure_duration integer,
+  -- Artwork for the post, such as a podcast episode's itunes:image
+  image_url text,
+  -- The full text as markdown, converted from the feed's HTML; only
+  -- loaded for the reading pane, while summary is the short preview
+  content text,
   foreign key (feed_id) references feed (id) on delete cascade,
-  unique (url, feed_id)
+  -- What keeps a post from being stored twice, even when two fetches of
+  -- the same feed race; CreatePost skips rows that conflict with it. Posts
+  -- may share a URL, as podcast episodes linking to the show page do.
+  unique (feed_id, guid)
+);
+
+-- Small key/value store for state that isn't tied to a feed or post
+create table setting (
+  key text primary key,
+  value text not null
 );
+
+-- Regular expressions matched against the titles of new posts, which are
+-- stored already archived when one matches; feed_id null applies to all
+create table mute (
+  id integer primary key,
+  pattern text not null,
+  feed_id integer,
+  foreign key (feed_id) references feed (id) on delete cascade
+);
+
+-- GUIDs of posts deleted for good, so fetching their feed again doesn't
+-- bring them back
+create table deleted_post (
+  feed_id integer not null,
+  guid text not null,
+  primary key (feed_id, guid),
+  foreign key (feed_id) references feed (id) on delete cascade
+);
+
+-- Full-text index over posts, kept in sync by the triggers below
+create virtual table post_search using fts5 (
+  title,
+  summary,
+  author,
+  content = 'post',
+  content_rowid = 'id'
+);
+
+create trigger post_search_insert after insert on post begin
+  insert into post_search (rowid, title, summary, author)
+  values (new.id, new.title, new.summary, new.author);
+end;
+
+create trigger post_search_delete after delete on post begin
+  insert into post_search (post_search, rowid, title, summary, author)
+  values ('delete', old.id, old.title, old.summary, old.author);
+end;
+
+create trigger post_search_update after update of title, summary, author on post begin
+  insert into post_search (post_search, rowid, title, summary, author)
+  values ('delete', old.id, old.title, old.summary, old.author);
+  insert into post_search (rowid, title, summary, author)
+  values (new.id, new.title, new.summary, new.author);
+end;
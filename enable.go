@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// setFeedEnabled implements `feeder enable <url-or-name>` and
+// `feeder disable <url-or-name>`. Disabled feeds are skipped when fetching
+// but keep their posts.
+func setFeedEnabled(ctx context.Context, queries *database.Queries, args []string, enabled bool) error {
+	command, status, isEnabled := "disable", "Disabled", int64(0)
+	if enabled {
+		command, status, isEnabled = "enable", "Enabled", 1
+	}
+
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: feeder %s <url-or-name>\n", command)
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New(command + " expects a feed URL or name")
+	}
+
+	feed, err := findFeed(ctx, queries, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	err = queries.SetFeedEnabled(ctx, database.SetFeedEnabledParams{
+		IsEnabled: sql.NullInt64{Int64: isEnabled, Valid: true},
+		ID:        feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to %s feed: %w", command, err)
+	}
+
+	fmt.Printf("%s feed %q\n", status, feed.Name)
+	return nil
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// exportedPost is the JSON form of a post written by `feeder export-posts`
+type exportedPost struct {
+	ID            int64    `json:"id"`
+	Title         string   `json:"title"`
+	URL           string   `json:"url"`
+	Feed          string   `json:"feed"`
+	FeedID        int64    `json:"feed_id"`
+	Published     string   `json:"published"`
+	DateEstimated bool     `json:"date_estimated"`
+	Author        string   `json:"author,omitempty"`
+	Categories    []string `json:"categories,omitempty"`
+	Summary       string   `json:"summary,omitempty"`
+	Read          bool     `json:"read"`
+	Starred       bool     `json:"starred"`
+	Archived      bool     `json:"archived"`
+}
+
+func newExportedPost(post database.PostWithFeed) exportedPost {
+	exported := exportedPost{
+		ID:            post.ID,
+		Title:         post.Title,
+		URL:           post.Url,
+		Feed:          post.FeedName,
+		FeedID:        post.FeedID,
+		Published:     post.PublishedAt,
+		DateEstimated: post.DateEstimated.Int64 != 0,
+		Author:        post.Author.String,
+		Summary:       post.Summary.String,
+		Read:          post.IsRead.Int64 != 0,
+		Starred:       post.IsStarred.Int64 != 0,
+		Archived:      post.IsArchived.Int64 != 0,
+	}
+	if post.Categories.Valid {
+		exported.Categories = strings.Split(post.Categories.String, ",")
+	}
+	return exported
+}
+
+// exportPosts implements `feeder export-posts`, writing the posts on one of
+// the TUI's screens to stdout for use in scripts or spreadsheets.
+func exportPosts(ctx context.Context, queries *database.Queries, args []string) error {
+	fs := flag.NewFlagSet("export-posts", flag.ExitOnError)
+	format := fs.String("format", "json", "output format: json or csv")
+	screen := fs.String("screen", "", "posts to export: inbox, starred or archive (default inbox for json, starred for csv)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder export-posts [-format json|csv] [-screen inbox|starred|archive]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("export-posts takes no arguments")
+	}
+
+	// A CSV is usually a reading list kept for later
+	if *screen == "" {
+		*screen = "inbox"
+		if *format == "csv" {
+			*screen = "starred"
+		}
+	}
+
+	var posts []database.PostWithFeed
+	var err error
+	switch *screen {
+	case "inbox":
+		posts, err = queries.ListInbox(ctx, database.ListOptions{})
+	case "starred":
+		posts, err = queries.ListStarred(ctx, database.ListOptions{})
+	case "archive":
+		posts, err = queries.ListArchive(ctx, database.ListOptions{})
+	default:
+		return fmt.Errorf("unknown screen %q", *screen)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list posts: %w", err)
+	}
+
+	switch *format {
+	case "json":
+		return writePostsJSON(os.Stdout, posts)
+	case "csv":
+		return writePostsCSV(os.Stdout, posts)
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+}
+
+func writePostsJSON(w io.Writer, posts []database.PostWithFeed) error {
+	exported := make([]exportedPost, len(posts))
+	for i, post := range posts {
+		exported[i] = newExportedPost(post)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(exported)
+}
+
+var csvHeader = []string{
+	"title", "url", "feed", "published", "author", "categories", "read", "starred", "archived",
+}
+
+func writePostsCSV(w io.Writer, posts []database.PostWithFeed) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, post := range posts {
+		exported := newExportedPost(post)
+		err := writer.Write([]string{
+			exported.Title,
+			exported.URL,
+			exported.Feed,
+			exported.Published,
+			exported.Author,
+			strings.Join(exported.Categories, ","),
+			strconv.FormatBool(exported.Read),
+			strconv.FormatBool(exported.Starred),
+			strconv.FormatBool(exported.Archived),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
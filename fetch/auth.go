@@ -0,0 +1,55 @@
+package fetch
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// Auth holds the credentials sent with requests for a private feed. Values
+// may reference environment variables as ${NAME}, which are expanded right
+// before each request so secrets don't have to be stored in the database.
+type Auth struct {
+	// User and Pass are sent as HTTP Basic auth when User is set
+	User string
+	Pass string
+	// Header is a complete header line such as "Authorization: Bearer x"
+	Header string
+}
+
+// FeedAuth returns the credentials stored on feed.
+func FeedAuth(feed database.Feed) Auth {
+	return Auth{
+		User:   feed.AuthUser.String,
+		Pass:   feed.AuthPass.String,
+		Header: feed.AuthHeader.String,
+	}
+}
+
+var envRef = regexp.MustCompile(`\$\{(\w+)\}`)
+
+func expandEnvRefs(value string) string {
+	return envRef.ReplaceAllStringFunc(value, func(ref string) string {
+		return os.Getenv(envRef.FindStringSubmatch(ref)[1])
+	})
+}
+
+func (a Auth) apply(request *http.Request) error {
+	if a.User != "" {
+		request.SetBasicAuth(expandEnvRefs(a.User), expandEnvRefs(a.Pass))
+	}
+	if a.Header != "" {
+		name, value, ok := strings.Cut(a.Header, ":")
+		// The header isn't quoted back, it may well hold a token, and the
+		// error is logged and stored with the feed
+		if !ok || strings.TrimSpace(name) == "" {
+			return errors.New("invalid auth header, expected \"Name: value\"")
+		}
+		request.Header.Set(strings.TrimSpace(name), expandEnvRefs(strings.TrimSpace(value)))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package fetch
+
+import (
+	"net/url"
+	"strings"
+)
+
+// DefaultTrackingParams are the query parameters removed from post URLs
+// unless configured otherwise. A trailing * matches any suffix.
+var DefaultTrackingParams = []string{
+	"utm_*",
+	"fbclid",
+	"gclid",
+	"dclid",
+	"msclkid",
+	"yclid",
+	"mc_cid",
+	"mc_eid",
+	"igshid",
+	"_hsenc",
+	"_hsmi",
+	"mkt_tok",
+	"ref_src",
+}
+
+// cleanURL removes the query parameters matching params from rawURL,
+// keeping the rest in their original order and encoding. URLs that can't
+// be parsed are returned unchanged.
+func cleanURL(rawURL string, params []string) string {
+	if len(params) == 0 || !strings.Contains(rawURL, "?") {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+
+	var kept []string
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		key, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(key); err == nil {
+			key = unescaped
+		}
+		if !isTrackingParam(key, params) {
+			kept = append(kept, pair)
+		}
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	u.ForceQuery = false
+	return u.String()
+}
+
+// resolveURL resolves ref against base, so relative post links point at
+// the site they came from. ref is returned unchanged when either can't be
+// parsed or there's no base.
+func resolveURL(base, ref string) string {
+	if ref == "" || base == "" {
+		return ref
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+func isTrackingParam(key string, params []string) bool {
+	key = strings.ToLower(key)
+	for _, param := range params {
+		param = strings.ToLower(param)
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		} else if key == param {
+			return true
+		}
+	}
+	return false
+}
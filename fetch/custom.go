@@ -0,0 +1,297 @@
+package fetch
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// CustomSelectors maps parts of an HTML page to feed items for feeds of type
+// "custom". Each selector is a whitespace-separated chain of simple CSS
+// selectors built from an optional tag name plus #id and .class parts, e.g.
+// "article h2.title". Title, Link and Date are matched inside each Item; an
+// empty selector refers to the item element itself.
+type CustomSelectors struct {
+	Item  string `json:"item"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+	Date  string `json:"date"`
+}
+
+func parseCustomSelectors(raw sql.NullString) (CustomSelectors, error) {
+	var selectors CustomSelectors
+	if !raw.Valid || raw.String == "" {
+		return selectors, errors.New("custom feed has no selectors configured")
+	}
+	if err := json.Unmarshal([]byte(raw.String), &selectors); err != nil {
+		return selectors, fmt.Errorf("error decoding custom selectors: %w", err)
+	}
+	if selectors.Item == "" {
+		return selectors, errors.New("custom selectors are missing an item selector")
+	}
+	return selectors, nil
+}
+
+// ValidateCustomSelectors checks the JSON selectors given for a custom feed,
+// e.g. {"item": "article", "title": "h2", "link": "a", "date": "time"}
+func ValidateCustomSelectors(raw string) error {
+	_, err := parseCustomSelectors(sql.NullString{String: raw, Valid: true})
+	return err
+}
+
+func getCustomFeed(pageURL string, body []byte, selectors CustomSelectors) ([]NormalizedItem, error) {
+	root, err := parseHTML(body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing HTML: %w", err)
+	}
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing page URL: %w", err)
+	}
+
+	var items []NormalizedItem
+	for _, element := range querySelectorAll(root, parseSelector(selectors.Item)) {
+		item := NormalizedItem{}
+
+		if title := querySelector(element, parseSelector(selectors.Title)); title != nil {
+			item.Title = title.text()
+		}
+
+		if link := querySelector(element, parseSelector(selectors.Link)); link != nil {
+			href, ok := link.attr("href")
+			if !ok {
+				if anchor := querySelector(link, parseSelector("a")); anchor != nil {
+					href, _ = anchor.attr("href")
+				}
+			}
+			if ref, err := url.Parse(strings.TrimSpace(href)); err == nil && href != "" {
+				item.URL = base.ResolveReference(ref).String()
+			}
+		}
+
+		if date := querySelector(element, parseSelector(selectors.Date)); date != nil {
+			if datetime, ok := date.attr("datetime"); ok {
+				item.Published = datetime
+			} else {
+				item.Published = date.text()
+			}
+		}
+
+		if item.URL == "" {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// htmlNode is a minimal element tree built from a parsed HTML page.
+type htmlNode struct {
+	name     string
+	attrs    []html.Attribute
+	children []*htmlNode
+	parent   *htmlNode
+	data     string // text content for text nodes, which have no name
+}
+
+func (n *htmlNode) attr(name string) (string, bool) {
+	for _, a := range n.attrs {
+		if strings.EqualFold(a.Key, name) {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func (n *htmlNode) text() string {
+	var sb strings.Builder
+	var walk func(*htmlNode)
+	walk = func(node *htmlNode) {
+		switch node.name {
+		case "":
+			sb.WriteString(node.data)
+			sb.WriteString(" ")
+		case "script", "style":
+			// Code, not text
+			return
+		}
+		for _, child := range node.children {
+			walk(child)
+		}
+	}
+	walk(n)
+	return strings.Join(strings.Fields(sb.String()), " ")
+}
+
+// parseHTML builds an element tree the way a browser parses the page, so
+// inline scripts, a bare < in text and unclosed elements don't end it early.
+func parseHTML(body []byte) (*htmlNode, error) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	return convertHTML(doc, nil), nil
+}
+
+// parseHTMLFragment parses a snippet of HTML, as feeds embed in their
+// items, the way it would be read inside <body>
+func parseHTMLFragment(fragment string) *htmlNode {
+	root := &htmlNode{name: "#document"}
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	// Reading from a string can't fail
+	nodes, _ := html.ParseFragment(strings.NewReader(fragment), body)
+	for _, node := range nodes {
+		if child := convertHTML(node, root); child != nil {
+			root.children = append(root.children, child)
+		}
+	}
+	return root
+}
+
+// convertHTML copies an element, text or document node and its children
+// into an htmlNode, leaving out comments and doctypes
+func convertHTML(node *html.Node, parent *htmlNode) *htmlNode {
+	converted := &htmlNode{parent: parent}
+	switch node.Type {
+	case html.DocumentNode:
+		converted.name = "#document"
+	case html.ElementNode:
+		converted.name = strings.ToLower(node.Data)
+		converted.attrs = node.Attr
+	case html.TextNode:
+		converted.data = node.Data
+		return converted
+	default:
+		return nil
+	}
+	for child := range node.ChildNodes() {
+		if child := convertHTML(child, converted); child != nil {
+			converted.children = append(converted.children, child)
+		}
+	}
+	return converted
+}
+
+type simpleSelector struct {
+	tag     string
+	id      string
+	classes []string
+}
+
+// parseSelector splits a selector into its descendant chain. An empty
+// selector yields an empty chain, which matches the starting element.
+func parseSelector(selector string) []simpleSelector {
+	var chain []simpleSelector
+	for _, part := range strings.Fields(selector) {
+		var sel simpleSelector
+		// Split "tag#id.class1.class2" on '#' and '.' while keeping the markers
+		var token strings.Builder
+		marker := byte(0)
+		flush := func() {
+			switch marker {
+			case 0:
+				sel.tag = strings.ToLower(token.String())
+			case '#':
+				sel.id = token.String()
+			case '.':
+				sel.classes = append(sel.classes, token.String())
+			}
+			token.Reset()
+		}
+		for i := 0; i < len(part); i++ {
+			if part[i] == '#' || part[i] == '.' {
+				flush()
+				marker = part[i]
+				continue
+			}
+			token.WriteByte(part[i])
+		}
+		flush()
+		chain = append(chain, sel)
+	}
+	return chain
+}
+
+func (s simpleSelector) matches(n *htmlNode) bool {
+	if n.name == "" {
+		return false
+	}
+	if s.tag != "" && s.tag != "*" && s.tag != n.name {
+		return false
+	}
+	if s.id != "" {
+		if id, _ := n.attr("id"); id != s.id {
+			return false
+		}
+	}
+	if len(s.classes) > 0 {
+		class, _ := n.attr("class")
+		have := strings.Fields(class)
+		for _, want := range s.classes {
+			found := false
+			for _, c := range have {
+				if c == want {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchesChain reports whether n matches the last selector in chain and its
+// ancestors, up to but excluding scope, match the rest in order.
+func matchesChain(n *htmlNode, chain []simpleSelector, scope *htmlNode) bool {
+	last := len(chain) - 1
+	if !chain[last].matches(n) {
+		return false
+	}
+	if last == 0 {
+		return true
+	}
+	for p := n.parent; p != nil && p != scope; p = p.parent {
+		if matchesChain(p, chain[:last], scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func querySelectorAll(scope *htmlNode, chain []simpleSelector) []*htmlNode {
+	if len(chain) == 0 {
+		return []*htmlNode{scope}
+	}
+
+	var matches []*htmlNode
+	var walk func(*htmlNode)
+	walk = func(node *htmlNode) {
+		for _, child := range node.children {
+			if matchesChain(child, chain, scope) {
+				matches = append(matches, child)
+			}
+			walk(child)
+		}
+	}
+	walk(scope)
+	return matches
+}
+
+func querySelector(scope *htmlNode, chain []simpleSelector) *htmlNode {
+	if matches := querySelectorAll(scope, chain); len(matches) > 0 {
+		return matches[0]
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package fetch
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetCustomFeedWithInlineScripts(t *testing.T) {
+	page := `<!DOCTYPE html>
+<html>
+<head>
+<script>if (a < b && b > c) { document.write("<p>"); }</script>
+</head>
+<body>
+<article class="post">
+  <h2 class="title">First <br> post</h2>
+  <a href="/posts/1">Read</a>
+  <time datetime="2024-01-02T03:04:05Z">Jan 2</time>
+</article>
+<script>for (let i = 0; i < 3; i++) {}</script>
+<article class="post">
+  <h2 class="title">Second &amp; last</h2>
+  <a href="https://other.example/2">Read</a>
+  <time>Jan 3, 2024</time>
+  <p>Unclosed paragraph
+</article>
+</body>
+</html>`
+	selectors := CustomSelectors{Item: "article.post", Title: "h2.title", Link: "a", Date: "time"}
+
+	items, err := getCustomFeed("https://example.com/blog/", []byte(page), selectors)
+	if err != nil {
+		t.Fatalf("getCustomFeed: %v", err)
+	}
+
+	want := []NormalizedItem{
+		{Title: "First post", URL: "https://example.com/posts/1", Published: "2024-01-02T03:04:05Z"},
+		{Title: "Second & last", URL: "https://other.example/2", Published: "Jan 3, 2024"},
+	}
+	if !slices.EqualFunc(items, want, func(a, b NormalizedItem) bool {
+		return a.Title == b.Title && a.URL == b.URL && a.Published == b.Published
+	}) {
+		t.Errorf("items = %+v, want %+v", items, want)
+	}
+}
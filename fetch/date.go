@@ -0,0 +1,229 @@
+package fetch
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayouts are tried in order after normalizeDate has removed the
+// weekday and turned zone abbreviations into offsets.
+var dateLayouts = []string{
+	// Atom and JSON Feed, fractional seconds are accepted too
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	// RSS, with and without seconds and with two-digit years
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 January 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05",
+	// Everything else seen in the wild
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"January 2, 2006 15:04:05",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+}
+
+// zoneOffsets covers the abbreviations feeds actually use. Go only resolves
+// abbreviations of the local zone and gives any other a zero offset.
+var zoneOffsets = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+	"AKST": "-0900",
+	"AKDT": "-0800",
+	"HST":  "-1000",
+	"BST":  "+0100",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"EET":  "+0200",
+	"EEST": "+0300",
+	"MSK":  "+0300",
+	"JST":  "+0900",
+	"KST":  "+0900",
+	"AEST": "+1000",
+	"AEDT": "+1100",
+	"NZST": "+1200",
+	"NZDT": "+1300",
+}
+
+var (
+	leadingWeekday = regexp.MustCompile(`^(?i)(mon|tue|wed|thu|fri|sat|sun)[a-z]*\.?,?\s+`)
+	trailingZone   = regexp.MustCompile(`\s\(?([A-Za-z]{1,4})\)?$`)
+)
+
+// normalizeDate trims and collapses whitespace, drops a leading weekday and
+// replaces a known trailing zone abbreviation with its numeric offset.
+func normalizeDate(dateStr string) string {
+	dateStr = strings.Join(strings.Fields(dateStr), " ")
+	dateStr = leadingWeekday.ReplaceAllString(dateStr, "")
+
+	if match := trailingZone.FindStringSubmatchIndex(dateStr); match != nil {
+		zone := strings.ToUpper(dateStr[match[2]:match[3]])
+		if offset, ok := zoneOffsets[zone]; ok {
+			dateStr = dateStr[:match[0]] + " " + offset
+		}
+	}
+	return dateStr
+}
+
+func parseDate(dateStr string) (time.Time, string, error) {
+	normalized := normalizeDate(dateStr)
+	for _, format := range dateLayouts {
+		if t, err := time.Parse(format, normalized); err == nil {
+			return t, format, nil
+		}
+	}
+
+	// The heuristic isn't a layout, so there is no format to remember
+	if t, ok := guessDate(normalized); ok {
+		return t, "", nil
+	}
+	return time.Time{}, "", fmt.Errorf("unable to parse date: %s", dateStr)
+}
+
+// parseDateWithFormat tries the feed's known layout before guessing. The
+// date is tried as given too, since a layout set by hand may spell out the
+// weekday or zone that normalizeDate removes.
+func parseDateWithFormat(dateStr string, knownFormat sql.NullString) (time.Time, string, error) {
+	if knownFormat.Valid && knownFormat.String != "" {
+		for _, value := range []string{normalizeDate(dateStr), strings.TrimSpace(dateStr)} {
+			if t, err := time.Parse(knownFormat.String, value); err == nil {
+				return t, knownFormat.String, nil
+			}
+		}
+	}
+
+	return parseDate(dateStr)
+}
+
+// ValidateDateLayout checks a Go time layout given for a feed's dates by
+// formatting a sample date with it and parsing the result back, which
+// fails for layouts that lose the day, such as one with the reference
+// date's parts misspelled.
+func ValidateDateLayout(layout string) error {
+	sample := time.Date(2009, time.November, 10, 23, 4, 5, 0, time.UTC)
+	parsed, err := time.Parse(layout, sample.Format(layout))
+	if err != nil {
+		return fmt.Errorf("date layout %q can't parse its own output: %w", layout, err)
+	}
+	if y, m, d := parsed.Date(); y != 2009 || m != time.November || d != 10 {
+		return fmt.Errorf("date layout %q has no full date, write it with the reference time Mon Jan 2 15:04:05 MST 2006", layout)
+	}
+	return nil
+}
+
+// mostCommonFormat returns the layout that parsed the most dates of a
+// fetch, given the layouts in the order they were first used and how often
+// each was. Ties keep the stored layout, then the one used first, so a
+// feed's format only changes when most of its dates call for it and not
+// because of the odd item in a different format.
+func mostCommonFormat(formats []string, counts map[string]int, stored string) string {
+	var best string
+	for _, format := range formats {
+		if counts[format] > counts[best] || (counts[format] == counts[best] && format == stored) {
+			best = format
+		}
+	}
+	return best
+}
+
+var (
+	isoDate     = regexp.MustCompile(`(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})`)
+	dayMonth    = regexp.MustCompile(`(?i)(\d{1,2})(?:st|nd|rd|th)?\.?\s+([a-z]{3,})\.?,?\s+(\d{4})`)
+	monthDay    = regexp.MustCompile(`(?i)([a-z]{3,})\.?\s+(\d{1,2})(?:st|nd|rd|th)?,?\s+(\d{4})`)
+	clockTime   = regexp.MustCompile(`(?i)(\d{1,2}):(\d{2})(?::(\d{2}))?\s*([ap])?\.?m?\b`)
+	zoneOffset  = regexp.MustCompile(`([+-])(\d{2}):?(\d{2})$`)
+	monthPrefix = map[string]time.Month{
+		"jan": time.January, "feb": time.February, "mar": time.March,
+		"apr": time.April, "may": time.May, "jun": time.June,
+		"jul": time.July, "aug": time.August, "sep": time.September,
+		"oct": time.October, "nov": time.November, "dec": time.December,
+	}
+)
+
+// guessDate is the last resort for dates no layout matches. It picks out a
+// year, month and day in any common order, plus a time of day and offset
+// when present, and assumes UTC otherwise.
+func guessDate(dateStr string) (time.Time, bool) {
+	var year, day int
+	var month time.Month
+
+	if m := isoDate.FindStringSubmatch(dateStr); m != nil {
+		year, _ = strconv.Atoi(m[1])
+		monthNumber, _ := strconv.Atoi(m[2])
+		month = time.Month(monthNumber)
+		day, _ = strconv.Atoi(m[3])
+	} else if m := dayMonth.FindStringSubmatch(dateStr); m != nil && lookupMonth(m[2]) != 0 {
+		day, _ = strconv.Atoi(m[1])
+		month = lookupMonth(m[2])
+		year, _ = strconv.Atoi(m[3])
+	} else if m := monthDay.FindStringSubmatch(dateStr); m != nil && lookupMonth(m[1]) != 0 {
+		month = lookupMonth(m[1])
+		day, _ = strconv.Atoi(m[2])
+		year, _ = strconv.Atoi(m[3])
+	} else {
+		return time.Time{}, false
+	}
+	if month < time.January || month > time.December || day < 1 || day > 31 {
+		return time.Time{}, false
+	}
+
+	var hour, minute, second int
+	if m := clockTime.FindStringSubmatch(dateStr); m != nil {
+		hour, _ = strconv.Atoi(m[1])
+		minute, _ = strconv.Atoi(m[2])
+		second, _ = strconv.Atoi(m[3])
+		switch strings.ToLower(m[4]) {
+		case "p":
+			if hour < 12 {
+				hour += 12
+			}
+		case "a":
+			if hour == 12 {
+				hour = 0
+			}
+		}
+	}
+
+	location := time.UTC
+	if m := zoneOffset.FindStringSubmatch(dateStr); m != nil {
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		offset := hours*3600 + minutes*60
+		if m[1] == "-" {
+			offset = -offset
+		}
+		location = time.FixedZone("", offset)
+	}
+
+	return time.Date(year, month, day, hour, minute, second, 0, location), true
+}
+
+func lookupMonth(name string) time.Month {
+	if len(name) < 3 {
+		return 0
+	}
+	return monthPrefix[strings.ToLower(name[:3])]
+}
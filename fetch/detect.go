@@ -0,0 +1,149 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	neturl "net/url"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownFeedType is returned by Detect when a document isn't a feed
+// format this package understands.
+var ErrUnknownFeedType = errors.New("unknown feed type")
+
+// DetectedFeed is what Detect found out about a feed.
+type DetectedFeed struct {
+	// URL is the feed's address, which differs from the one passed to
+	// Detect when it was discovered from an HTML page
+	URL   string
+	Type  string
+	Title string
+}
+
+// Detect downloads url with auth and works out its feed type from the
+// Content-Type header or the document's root element, along with the feed's
+// own title. When url is an HTML page that links to a feed, that feed is
+// detected instead.
+func Detect(ctx context.Context, opts Options, url string, auth Auth) (DetectedFeed, error) {
+	return detect(ctx, opts, url, auth, true)
+}
+
+func detect(ctx context.Context, opts Options, url string, auth Auth, discover bool) (DetectedFeed, error) {
+	body, header, err := fetchBody(ctx, opts.withDefaults(), url, auth, &cacheHeaders{})
+	if err != nil {
+		return DetectedFeed{}, err
+	}
+
+	detected := DetectedFeed{URL: url, Type: sniffFeedType(header.Get("Content-Type"), body)}
+	switch detected.Type {
+	case "rss":
+		var rss RSS
+		if err := parseFeed(body, &rss); err != nil {
+			return DetectedFeed{}, fmt.Errorf("error parsing XML: %w", err)
+		}
+		detected.Title = strings.TrimSpace(rss.Channel.Title)
+	case "rdf":
+		var rdf RDF
+		if err := parseFeed(body, &rdf); err != nil {
+			return DetectedFeed{}, fmt.Errorf("error parsing XML: %w", err)
+		}
+		detected.Title = strings.TrimSpace(rdf.Channel.Title)
+	case "atom":
+		var atom Atom
+		if err := parseFeed(body, &atom); err != nil {
+			return DetectedFeed{}, fmt.Errorf("error parsing XML: %w", err)
+		}
+		detected.Title = strings.TrimSpace(atom.Title.PlainText())
+	case "json":
+		var jsonFeed JSONFeed
+		if err := parseFeed(body, &jsonFeed); err != nil {
+			return DetectedFeed{}, fmt.Errorf("error parsing JSON: %w", err)
+		}
+		detected.Title = strings.TrimSpace(jsonFeed.Title)
+	default:
+		// Homepages usually advertise their feed; only follow one link so
+		// pages pointing at each other can't loop
+		if feedURL := discoverFeedURL(url, body); discover && feedURL != "" && feedURL != url {
+			return detect(ctx, opts, feedURL, auth, false)
+		}
+		return DetectedFeed{}, fmt.Errorf("%w at %s", ErrUnknownFeedType, url)
+	}
+	return detected, nil
+}
+
+// feedLinkTypes are the link types that point at a feed this package reads
+var feedLinkTypes = []string{
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/feed+json",
+}
+
+// discoverFeedURL returns the first feed an HTML page links to with
+// <link rel="alternate">, resolved against pageURL, or "" if there is none.
+func discoverFeedURL(pageURL string, body []byte) string {
+	base, err := neturl.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+
+	// Keep whatever was parsed before an error; the links are in the head
+	root, _ := parseHTML(body)
+	for _, link := range querySelectorAll(root, parseSelector("link")) {
+		rel, _ := link.attr("rel")
+		if !slices.Contains(strings.Fields(strings.ToLower(rel)), "alternate") {
+			continue
+		}
+		linkType, _ := link.attr("type")
+		if !slices.Contains(feedLinkTypes, strings.ToLower(strings.TrimSpace(linkType))) {
+			continue
+		}
+		href, _ := link.attr("href")
+		ref, err := neturl.Parse(strings.TrimSpace(href))
+		if err != nil || href == "" {
+			continue
+		}
+		return base.ResolveReference(ref).String()
+	}
+	return ""
+}
+
+// sniffFeedType prefers an explicit Content-Type and otherwise inspects the
+// body, returning "" when neither identifies a feed.
+func sniffFeedType(contentType string, body []byte) string {
+	// Servers often label feeds loosely, e.g. Atom as text/xml or even
+	// application/rss+xml, so the document itself is the better witness
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return "json"
+	}
+
+	decoder := newXMLDecoder(body)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return feedTypeFromContentType(contentType)
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			switch start.Name.Local {
+			case "rss":
+				return "rss"
+			case "RDF":
+				return "rdf"
+			case "feed":
+				return "atom"
+			default:
+				return ""
+			}
+		}
+	}
+}
+
+// looksLikeHTML reports whether body starts as an HTML page does
+func looksLikeHTML(body []byte) bool {
+	start := bytes.TrimPrefix(bytes.TrimSpace(body), []byte("\ufeff"))
+	start = bytes.ToLower(start[:min(len(start), len("<!doctype html"))])
+	return bytes.HasPrefix(start, []byte("<!doctype html")) || bytes.HasPrefix(start, []byte("<html"))
+}
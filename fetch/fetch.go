@@ -0,0 +1,313 @@
+// Package fetch downloads feeds, normalizes their items and stores new posts.
+package fetch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+const (
+	DefaultConcurrency = 8
+	DefaultTimeout     = 30 * time.Second
+	DefaultMaxRetries  = 2
+	DefaultUserAgent   = "feeder/1.0 (+https://github.com/aaronzipp/feeder)"
+)
+
+// Options configures a refresh run. Zero values fall back to the defaults,
+// except MaxRetries where zero disables retrying.
+type Options struct {
+	Client      *http.Client
+	Concurrency int
+	MaxRetries  int
+	// UserAgent is sent with every request, since some hosts block Go's
+	// default agent
+	UserAgent string
+	// Logger receives each feed's result and any problems on the way; nil
+	// discards them
+	Logger *slog.Logger
+	// MinInterval skips feeds that were checked more recently than this;
+	// zero fetches every feed
+	MinInterval time.Duration
+	// TrackingParams are removed from post URLs before they are stored, so
+	// links are cleaner and the same article isn't stored twice; nil uses
+	// DefaultTrackingParams and an empty list keeps URLs as they are
+	TrackingParams []string
+	// DryRun fetches and parses feeds and stores them as usual, but rolls
+	// every feed's writes back afterwards. The writes are logged at debug
+	// level.
+	DryRun bool
+	// Backfill is how many older pages of a paginated feed (RFC 5005) to
+	// follow after the first, to import history the feed document alone
+	// doesn't carry; zero fetches only the feed document
+	Backfill int
+}
+
+// DefaultOptions returns the options used when nothing is configured.
+func DefaultOptions() Options {
+	return Options{MaxRetries: DefaultMaxRetries}.withDefaults()
+}
+
+func (o Options) withDefaults() Options {
+	if o.Client == nil {
+		o.Client = &http.Client{Timeout: DefaultTimeout}
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = DefaultConcurrency
+	}
+	if o.UserAgent == "" {
+		o.UserAgent = DefaultUserAgent
+	}
+	if o.Logger == nil {
+		o.Logger = slog.New(slog.DiscardHandler)
+	}
+	if o.TrackingParams == nil {
+		o.TrackingParams = DefaultTrackingParams
+	}
+	return o
+}
+
+// Summary totals the outcome of a Refresh.
+type Summary struct {
+	// Checked counts the feeds that were fetched, whatever came of it
+	Checked     int
+	NotModified int
+	Failed      int
+	// NotDue counts enabled feeds skipped because they were checked
+	// recently
+	NotDue   int
+	Disabled int
+	// New counts the posts that weren't stored before
+	New      int64
+	Duration time.Duration
+}
+
+// Refresh fetches every enabled feed and stores any new posts. Fetching happens in
+// parallel, but all database writes stay on the calling goroutine since
+// SQLite doesn't cope well with concurrent writers. Cancelling ctx stops
+// new fetches from starting; feeds already being fetched are finished and
+// stored.
+func Refresh(ctx context.Context, queries *database.Queries, opts Options) (Summary, error) {
+	opts = opts.withDefaults()
+	start := time.Now()
+
+	feeds, err := queries.ListEnabledFeeds(ctx)
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to list feeds: %w", err)
+	}
+	disabled, err := queries.CountDisabledFeeds(ctx)
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to count disabled feeds: %w", err)
+	}
+	due := dueFeeds(feeds, opts.MinInterval, start)
+	opts.Logger.Debug("Refreshing feeds", "due", len(due), "skipped", len(feeds)-len(due))
+
+	summary := Summary{NotDue: len(feeds) - len(due), Disabled: int(disabled)}
+	return refreshFeeds(ctx, queries, opts, due, summary, start)
+}
+
+// RefreshFailed fetches only the enabled feeds whose last fetch failed,
+// whenever they were last checked, which is quicker than a full Refresh
+// after an outage. Feeds that succeed have their error cleared.
+func RefreshFailed(ctx context.Context, queries *database.Queries, opts Options) (Summary, error) {
+	opts = opts.withDefaults()
+	start := time.Now()
+
+	feeds, err := queries.ListFailedFeeds(ctx)
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to list failed feeds: %w", err)
+	}
+	opts.Logger.Debug("Retrying failed feeds", "feeds", len(feeds))
+
+	return refreshFeeds(ctx, queries, opts, feeds, Summary{}, start)
+}
+
+// refreshFeeds fetches feeds and stores their posts, adding the outcome to
+// summary
+func refreshFeeds(
+	ctx context.Context,
+	queries *database.Queries,
+	opts Options,
+	feeds []database.Feed,
+	summary Summary,
+	start time.Time,
+) (Summary, error) {
+	for result := range fetchAll(ctx, opts, feeds) {
+		n, err := opts.store(context.WithoutCancel(ctx), queries, result)
+		summary.Checked++
+		summary.New += n
+		switch {
+		case err != nil:
+			summary.Failed++
+		case errors.Is(result.err, errNotModified):
+			summary.NotModified++
+		}
+	}
+	summary.Duration = time.Since(start)
+	opts.Logger.Info("Refreshed feeds",
+		"checked", summary.Checked,
+		"failed", summary.Failed,
+		"new", summary.New,
+		"duration", summary.Duration.Round(time.Millisecond),
+	)
+
+	return summary, ctx.Err()
+}
+
+// FetchAndStore fetches a single feed, whether or not it's enabled or due,
+// and stores any new posts. It returns how many were new and the error that
+// prevented the feed from being fetched or parsed.
+func FetchAndStore(ctx context.Context, queries *database.Queries, feed database.Feed, opts Options) (int64, error) {
+	opts = opts.withDefaults()
+
+	result, ok := fetchFeed(ctx, opts, feed)
+	if !ok {
+		return 0, fmt.Errorf("unsupported feed type %q", feed.FeedType)
+	}
+	return opts.store(ctx, queries, result)
+}
+
+// store runs storeFeed, inside a transaction that is rolled back for a dry
+// run so duplicates and date formats are still detected against what's
+// stored.
+func (o Options) store(ctx context.Context, queries *database.Queries, result fetchResult) (int64, error) {
+	if !o.DryRun {
+		return storeFeed(ctx, queries, result, o.Logger)
+	}
+
+	var added int64
+	var err error
+	txErr := queries.RolledBack(ctx, func(queries *database.Queries) {
+		added, err = storeFeed(ctx, queries, result, o.Logger)
+	})
+	if txErr != nil {
+		return 0, fmt.Errorf("failed to start dry run: %w", txErr)
+	}
+	return added, err
+}
+
+type fetchResult struct {
+	feed database.Feed
+	parsedFeed
+	cache cacheHeaders
+	// feedType is the format the document was parsed as, which is stored
+	// when it differs from the feed's
+	feedType string
+	err      error
+}
+
+func fetchFeed(ctx context.Context, opts Options, feed database.Feed) (fetchResult, bool) {
+	result := fetchResult{
+		feed: feed,
+		cache: cacheHeaders{
+			ETag:         feed.Etag.String,
+			LastModified: feed.LastModified.String,
+		},
+	}
+
+	switch feed.FeedType {
+	case "rss", "rdf", "atom", "json", "custom":
+	default:
+		return result, false
+	}
+
+	// A backfill is after the older pages, which a 304 for the first one
+	// would skip
+	if opts.Backfill > 0 {
+		result.cache = cacheHeaders{}
+	}
+	body, header, err := fetchBody(ctx, opts, feed.Url, FeedAuth(feed), &result.cache)
+	if err != nil {
+		result.err = err
+		return result, true
+	}
+
+	// Parse what the document turns out to be rather than what was stored,
+	// so a mislabeled feed or one that switched formats still has items.
+	// Custom feeds are HTML pages, which sniff as nothing.
+	result.feedType = feed.FeedType
+	if detected := sniffFeedType(header.Get("Content-Type"), body); feed.FeedType != "custom" && detected != "" {
+		result.feedType = detected
+	}
+
+	switch result.feedType {
+	case "rss":
+		result.parsedFeed, result.err = getRSSFeed(body)
+	case "rdf":
+		result.parsedFeed, result.err = getRDFFeed(body)
+	case "atom":
+		result.parsedFeed, result.err = getAtomFeed(body)
+	case "json":
+		result.parsedFeed, result.err = getJSONFeed(body)
+	case "custom":
+		var selectors CustomSelectors
+		selectors, result.err = parseCustomSelectors(feed.CustomSelectors)
+		if result.err == nil {
+			result.items, result.err = getCustomFeed(feed.Url, body, selectors)
+		}
+	}
+
+	// Misconfigured servers answer with an error or landing page labeled as
+	// a feed, which parses as one without items rather than failing
+	if result.err == nil && len(result.items) == 0 && result.feedType != "custom" && looksLikeHTML(body) {
+		result.err = fmt.Errorf("got an HTML page instead of a feed (Content-Type %q)", header.Get("Content-Type"))
+	}
+
+	if result.err == nil {
+		opts.Logger.Debug("Parsed feed", "feed", feed.Name, "type", result.feedType, "items", len(result.items))
+	}
+	if result.err == nil && opts.Backfill > 0 && result.feedType != "custom" {
+		next := nextPageURL(feed.Url, header, result.feedType, body)
+		result.items = append(result.items, fetchOlderPages(ctx, opts, feed, result.feedType, next)...)
+	}
+
+	// Relative links are resolved against the feed's URL, and cleaned
+	// before the GUID falls back to the URL, so links that differ only in
+	// tracking parameters are deduplicated
+	for i := range result.items {
+		item := &result.items[i]
+		item.URL = cleanURL(resolveURL(feed.Url, item.URL), opts.TrackingParams)
+		item.Enclosure.URL = resolveURL(feed.Url, item.Enclosure.URL)
+		item.Image = resolveURL(feed.Url, item.Image)
+	}
+
+	return result, true
+}
+
+// fetchAll fetches feeds in parallel, running at most opts.Concurrency
+// requests at once. Results are delivered on the returned channel, which is
+// closed once every feed has been processed or skipped because ctx was
+// cancelled.
+func fetchAll(ctx context.Context, opts Options, feeds []database.Feed) <-chan fetchResult {
+	results := make(chan fetchResult)
+	sem := make(chan struct{}, max(1, opts.Concurrency))
+
+	var wg sync.WaitGroup
+	for _, feed := range feeds {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+			// A fetch that has started is allowed to finish
+			if result, ok := fetchFeed(context.WithoutCancel(ctx), opts, feed); ok {
+				results <- result
+			}
+		})
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
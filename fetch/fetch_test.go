@@ -0,0 +1,69 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+func TestFetchFeedResolvesRelativeLinks(t *testing.T) {
+	feeds := map[string]string{
+		"/blog/feed.atom": `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xml:base="/site/">
+  <title>Blog</title>
+  <entry><id>1</id><title>Feed base</title><link href="posts/1"/></entry>
+  <entry xml:base="https://cdn.example.com/archive/">
+    <id>2</id><title>Entry base</title>
+    <link href="2.html"/>
+    <link rel="enclosure" type="audio/mpeg" href="../audio/2.mp3"/>
+  </entry>
+  <entry><id>3</id><title>Link base</title><link xml:base="/other/" href="3"/></entry>
+  <entry><id>4</id><title>Absolute</title><link href="https://example.org/4?utm_source=feed"/></entry>
+</feed>`,
+		"/blog/feed.rss": `<?xml version="1.0"?>
+<rss version="2.0"><channel><title>Blog</title>
+  <item><guid>1</guid><title>Relative</title><link>posts/1</link></item>
+  <item><guid>2</guid><title>Root</title><link>/posts/2</link></item>
+</channel></rss>`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(feeds[r.URL.Path]))
+	}))
+	defer server.Close()
+
+	// Each item's link, then its enclosure
+	tests := []struct {
+		path     string
+		feedType string
+		want     [][2]string
+	}{
+		{"/blog/feed.atom", "atom", [][2]string{
+			{server.URL + "/site/posts/1", ""},
+			{"https://cdn.example.com/archive/2.html", "https://cdn.example.com/audio/2.mp3"},
+			{server.URL + "/other/3", ""},
+			{"https://example.org/4", ""},
+		}},
+		{"/blog/feed.rss", "rss", [][2]string{
+			{server.URL + "/blog/posts/1", ""},
+			{server.URL + "/posts/2", ""},
+		}},
+	}
+	for _, test := range tests {
+		feed := database.Feed{Name: "Blog", Url: server.URL + test.path, FeedType: test.feedType}
+		result, ok := fetchFeed(context.Background(), DefaultOptions(), feed)
+		if !ok || result.err != nil {
+			t.Fatalf("fetchFeed(%s) = %v, %v", test.path, ok, result.err)
+		}
+		if len(result.items) != len(test.want) {
+			t.Fatalf("fetchFeed(%s) got %d items, want %d", test.path, len(result.items), len(test.want))
+		}
+		for i, item := range result.items {
+			if got := [2]string{item.URL, item.Enclosure.URL}; got != test.want[i] {
+				t.Errorf("%s item %q links = %q, want %q", test.path, item.Title, got, test.want[i])
+			}
+		}
+	}
+}
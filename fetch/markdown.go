@@ -0,0 +1,189 @@
+package fetch
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// maxContentLength bounds the stored content of a post, in runes, so a feed
+// embedding whole books doesn't bloat the database
+const maxContentLength = 100_000
+
+// hardSpace stands in for indentation and preformatted spaces while the
+// markdown is assembled, so trimming the whitespace left over from the
+// HTML's formatting doesn't remove them
+const hardSpace = "\x00"
+
+var (
+	spaceRun   = regexp.MustCompile(`[ \t\r\n\f]+`)
+	blankLines = regexp.MustCompile(`\n{3,}`)
+)
+
+// htmlToMarkdown converts an HTML fragment to markdown that reads well as
+// plain text in a terminal: paragraphs, headings, lists, quotes, code
+// blocks, emphasis and links are kept, scripts, styles and other markup
+// are dropped.
+func htmlToMarkdown(fragment string) string {
+	markdown := trimLines(renderMarkdown(parseHTMLFragment(fragment), false))
+	markdown = blankLines.ReplaceAllString(markdown, "\n\n")
+	markdown = strings.ReplaceAll(strings.TrimSpace(markdown), hardSpace, " ")
+	return truncateRunes(markdown, maxContentLength)
+}
+
+// renderMarkdown converts node and its children, with pre set inside
+// preformatted text where whitespace is kept as is
+func renderMarkdown(node *htmlNode, pre bool) string {
+	if node.name == "" {
+		if pre {
+			text := strings.ReplaceAll(node.data, "\t", "    ")
+			return strings.ReplaceAll(text, " ", hardSpace)
+		}
+		return spaceRun.ReplaceAllString(node.data, " ")
+	}
+
+	switch node.name {
+	case "script", "style", "noscript", "template", "iframe", "object", "svg", "head":
+		return ""
+	case "br":
+		return "\n"
+	case "hr":
+		return block("---")
+	case "img":
+		if alt, _ := node.attr("alt"); strings.TrimSpace(alt) != "" {
+			return "[image: " + strings.TrimSpace(alt) + "]"
+		}
+		return ""
+	case "ul", "ol":
+		return block(renderList(node, pre))
+	}
+
+	var sb strings.Builder
+	for _, child := range node.children {
+		sb.WriteString(renderMarkdown(child, pre || node.name == "pre"))
+	}
+	inner := sb.String()
+
+	switch node.name {
+	case "p", "div", "section", "article", "header", "footer", "main", "aside",
+		"figure", "figcaption", "table", "tr", "dl", "dt", "dd", "details", "summary":
+		return block(inner)
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level, _ := strconv.Atoi(node.name[1:])
+		return block(strings.Repeat("#", level) + " " + strings.Join(strings.Fields(inner), " "))
+	case "pre":
+		return block("```\n" + strings.Trim(inner, "\n") + "\n```")
+	case "blockquote":
+		quote := blankLines.ReplaceAllString(trimLines(inner), "\n\n")
+		return block(prefixLines(quote, ">"+hardSpace))
+	case "li":
+		// Outside a list, as in broken markup
+		return block("-" + hardSpace + strings.TrimSpace(inner))
+	case "td", "th":
+		return inner + " "
+	case "strong", "b":
+		return emphasize(inner, "**")
+	case "em", "i":
+		return emphasize(inner, "*")
+	case "code":
+		if pre {
+			return inner
+		}
+		return emphasize(inner, "`")
+	case "a":
+		return renderLink(node, inner)
+	default:
+		return inner
+	}
+}
+
+// renderList numbers or bullets the items of a list, indenting whatever
+// follows an item's first line, such as nested lists, under its marker
+func renderList(list *htmlNode, pre bool) string {
+	var items []string
+	for _, child := range list.children {
+		if child.name != "li" {
+			continue
+		}
+		marker := "-"
+		if list.name == "ol" {
+			marker = fmt.Sprintf("%d.", len(items)+1)
+		}
+		marker += hardSpace
+
+		var sb strings.Builder
+		for _, grandchild := range child.children {
+			sb.WriteString(renderMarkdown(grandchild, pre))
+		}
+		content := blankLines.ReplaceAllString(trimLines(sb.String()), "\n\n")
+		content = strings.ReplaceAll(strings.Trim(content, "\n"), "\n\n", "\n")
+		indent := strings.Repeat(hardSpace, len(marker))
+		items = append(items, marker+strings.ReplaceAll(content, "\n", "\n"+indent))
+	}
+	return strings.Join(items, "\n")
+}
+
+func renderLink(node *htmlNode, inner string) string {
+	href, _ := node.attr("href")
+	href = strings.TrimSpace(href)
+	text := strings.TrimSpace(inner)
+	switch {
+	case href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(href, "javascript:"):
+		return inner
+	case text == "":
+		return ""
+	case text == href:
+		return "<" + href + ">"
+	}
+	return surround(inner, "["+text+"]("+href+")")
+}
+
+// emphasize wraps the text of an inline element in marker, keeping the
+// surrounding spaces outside of it
+func emphasize(inner, marker string) string {
+	text := strings.TrimSpace(inner)
+	if text == "" {
+		return inner
+	}
+	return surround(inner, marker+text+marker)
+}
+
+// surround replaces the trimmed text of inner with replacement, keeping the
+// spaces around it that separate it from neighbouring text
+func surround(inner, replacement string) string {
+	var before, after string
+	if strings.HasPrefix(inner, " ") {
+		before = " "
+	}
+	if strings.HasSuffix(inner, " ") {
+		after = " "
+	}
+	return before + replacement + after
+}
+
+func block(s string) string {
+	return "\n\n" + strings.Trim(s, " \n") + "\n\n"
+}
+
+// trimLines removes the spaces around every line, which are left over from
+// collapsing the HTML's whitespace
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.Trim(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func prefixLines(s, prefix string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	for i, line := range lines {
+		if line == "" {
+			lines[i] = strings.TrimRight(prefix, hardSpace)
+		} else {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,22 @@
+package fetch
+
+import "testing"
+
+func TestHTMLToMarkdown(t *testing.T) {
+	tests := []struct {
+		fragment string
+		want     string
+	}{
+		{"x < y, <b>bold</b>", "x < y, **bold**"},
+		{"<p>One</p><p>Two <em>words</em></p>", "One\n\nTwo *words*"},
+		{`<p>See <a href="https://example.com/">the site</a></p>`, "See [the site](https://example.com/)"},
+		{"<ul><li>a</li><li>b</li></ul>", "- a\n- b"},
+		{"<script>if (a < b) {}</script><p>After</p>", "After"},
+		{"<pre><code>if a < b {\n\treturn\n}</code></pre>", "```\nif a < b {\n    return\n}\n```"},
+	}
+	for _, test := range tests {
+		if got := htmlToMarkdown(test.fragment); got != test.want {
+			t.Errorf("htmlToMarkdown(%q) = %q, want %q", test.fragment, got, test.want)
+		}
+	}
+}
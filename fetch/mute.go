@@ -0,0 +1,56 @@
+package fetch
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// CompileMute compiles a mute pattern the way fetches match it, as a
+// case-insensitive regular expression that may match anywhere in a title.
+// RE2 runs in linear time, so no pattern can stall a fetch.
+func CompileMute(pattern string) (*regexp.Regexp, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("empty mute pattern would match every post")
+	}
+	mute, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mute pattern %q: %w", pattern, err)
+	}
+	return mute, nil
+}
+
+// loadMutes returns the compiled patterns muting posts of a feed, both its
+// own and the global ones. Patterns that don't compile, which the mute
+// command rejects but the database might still hold, are logged and
+// skipped rather than failing the fetch.
+func loadMutes(ctx context.Context, queries *database.Queries, feedID int64, log *slog.Logger) ([]*regexp.Regexp, error) {
+	patterns, err := queries.ListFeedMutes(ctx, sql.NullInt64{Int64: feedID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed listing mutes: %w", err)
+	}
+	var mutes []*regexp.Regexp
+	for _, pattern := range patterns {
+		mute, err := CompileMute(pattern)
+		if err != nil {
+			log.Warn("Ignoring mute pattern", "err", err)
+			continue
+		}
+		mutes = append(mutes, mute)
+	}
+	return mutes, nil
+}
+
+// isMuted reports whether any of mutes matches title
+func isMuted(mutes []*regexp.Regexp, title string) bool {
+	for _, mute := range mutes {
+		if mute.MatchString(title) {
+			return true
+		}
+	}
+	return false
+}
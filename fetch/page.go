@@ -0,0 +1,147 @@
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// pageRels are the link relations that point at older entries, in order of
+// preference: "next" pages through a paged feed and "prev-archive" walks
+// back through an archived one (RFC 5005)
+var pageRels = []string{"next", "prev-archive"}
+
+// fetchOlderPages follows next links from the first page of a feed, fetching
+// at most opts.Backfill pages, and returns their items with links resolved
+// against the page they came from. A page that fails ends the backfill
+// without failing the fetch, since the feed document itself was fine.
+func fetchOlderPages(ctx context.Context, opts Options, feed database.Feed, feedType, next string) []NormalizedItem {
+	log := opts.Logger.With("feed", feed.Name)
+	seen := map[string]bool{feed.Url: true}
+
+	var items []NormalizedItem
+	for page := 1; page <= opts.Backfill && next != "" && !seen[next]; page++ {
+		seen[next] = true
+		pageURL := next
+
+		body, header, err := fetchBody(ctx, opts, pageURL, FeedAuth(feed), &cacheHeaders{})
+		if err != nil {
+			log.Warn("Failed fetching older page, stopping the backfill", "url", pageURL, "err", err)
+			break
+		}
+		pageType := feedType
+		if detected := sniffFeedType(header.Get("Content-Type"), body); detected != "" {
+			pageType = detected
+		}
+		pageItems, err := parsePage(pageType, body)
+		if err != nil {
+			log.Warn("Failed parsing older page, stopping the backfill", "url", pageURL, "err", err)
+			break
+		}
+		log.Debug("Fetched older page", "url", pageURL, "page", page, "items", len(pageItems))
+		if len(pageItems) == 0 {
+			break
+		}
+
+		for i := range pageItems {
+			item := &pageItems[i]
+			item.URL = resolveURL(pageURL, item.URL)
+			item.Enclosure.URL = resolveURL(pageURL, item.Enclosure.URL)
+			item.Image = resolveURL(pageURL, item.Image)
+		}
+		items = append(items, pageItems...)
+		next = nextPageURL(pageURL, header, pageType, body)
+	}
+	return items
+}
+
+// parsePage reads the items of one page of a feed
+func parsePage(feedType string, body []byte) ([]NormalizedItem, error) {
+	var parsed parsedFeed
+	var err error
+	switch feedType {
+	case "rss":
+		parsed, err = getRSSFeed(body)
+	case "rdf":
+		parsed, err = getRDFFeed(body)
+	case "atom":
+		parsed, err = getAtomFeed(body)
+	case "json":
+		parsed, err = getJSONFeed(body)
+	}
+	return parsed.items, err
+}
+
+// nextPageURL returns the absolute URL of the page following the one at
+// pageURL, from the response's Link header or else the document's own
+// links, or "" on the last page
+func nextPageURL(pageURL string, header http.Header, feedType string, body []byte) string {
+	for _, rel := range pageRels {
+		if href := linkHeaderURL(header.Values("Link"), rel); href != "" {
+			return resolveURL(pageURL, href)
+		}
+	}
+
+	var links []AtomLink
+	switch feedType {
+	case "rss":
+		var rss RSS
+		if parseFeed(body, &rss) == nil {
+			links = rss.Channel.Links
+		}
+	case "atom":
+		var atom Atom
+		if parseFeed(body, &atom) == nil {
+			links = atom.Links
+			for i := range links {
+				if links[i].Base == "" {
+					links[i].Base = atom.Base
+				} else {
+					links[i].Base = resolveURL(atom.Base, links[i].Base)
+				}
+			}
+		}
+	case "json":
+		var jsonFeed JSONFeed
+		if parseFeed(body, &jsonFeed) == nil {
+			return resolveURL(pageURL, strings.TrimSpace(jsonFeed.NextURL))
+		}
+	}
+	for _, rel := range pageRels {
+		for _, link := range links {
+			if link.Rel == rel && strings.TrimSpace(link.Href) != "" {
+				return resolveURL(pageURL, link.href())
+			}
+		}
+	}
+	return ""
+}
+
+// linkHeaderURL returns the target of the first link with relation rel in
+// Link headers such as `<https://example.com/feed?page=2>; rel="next"`
+// (RFC 8288)
+func linkHeaderURL(values []string, rel string) string {
+	for _, value := range values {
+		for link := range strings.SplitSeq(value, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for param := range strings.SplitSeq(params, ";") {
+				name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				// rel may list several space separated relations
+				for _, r := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+					if strings.EqualFold(r, rel) {
+						return strings.TrimSpace(target[1 : len(target)-1])
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
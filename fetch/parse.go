@@ -0,0 +1,652 @@
+package fetch
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RawFeed interface {
+	RSS | RDF | Atom | JSONFeed
+}
+
+type RSS struct {
+	Channel Channel `xml:"channel"`
+}
+
+type Channel struct {
+	Title       string    `xml:"title"`
+	Description string    `xml:"description"`
+	Items       []RSSItem `xml:"item"`
+	LastUpdated string    `xml:"lastBuildDate"`
+	// TTL is in minutes
+	TTL             string `xml:"ttl"`
+	UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+	// Image is the podcast's artwork, used for episodes without their own
+	Image ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	// Links are the Atom links some RSS feeds borrow to point at their
+	// next page
+	Links []AtomLink `xml:"http://www.w3.org/2005/Atom link"`
+}
+
+type RSSItem struct {
+	GUID        string `xml:"guid"`
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Published   string `xml:"pubDate"`
+	Description string `xml:"description"`
+	// Content is the full article, where Description is often an excerpt
+	Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	// Author is usually an email address, so most feeds use the Dublin
+	// Core creator for the name instead
+	Author     string         `xml:"author"`
+	Creator    string         `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories []string       `xml:"category"`
+	Enclosures []RSSEnclosure `xml:"enclosure"`
+	// Podcasts describe their episodes in the iTunes namespace, which
+	// gives the running time and artwork that RSS has no place for
+	Duration      string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesSummary string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	Image         ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+}
+
+type ITunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// parseDuration reads an itunes:duration, which is either a number of
+// seconds or [HH:]MM:SS, returning 0 when it can't be read.
+func parseDuration(value string) time.Duration {
+	var seconds float64
+	for part := range strings.SplitSeq(strings.TrimSpace(value), ":") {
+		n, err := strconv.ParseFloat(part, 64)
+		if err != nil || n < 0 {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second)
+}
+
+// RSSEnclosure is media attached to an item. RSS allows one per item, but
+// some feeds list several.
+type RSSEnclosure struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+	// Length is in bytes, though feeds often leave it empty or write 0
+	Length string `xml:"length,attr"`
+}
+
+// parseLength reads an enclosure length attribute, treating anything that
+// isn't a positive number as unknown.
+func parseLength(length string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(length), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// enclosure returns the first enclosure that has a URL.
+func (i RSSItem) enclosure() Enclosure {
+	for _, enclosure := range i.Enclosures {
+		if url := strings.TrimSpace(enclosure.URL); url != "" {
+			return Enclosure{
+				URL:      url,
+				Type:     strings.TrimSpace(enclosure.Type),
+				Length:   parseLength(enclosure.Length),
+				Duration: parseDuration(i.Duration),
+			}
+		}
+	}
+	return Enclosure{}
+}
+
+// authorName prefers dc:creator and otherwise extracts the name from an
+// RSS author such as "jane@example.com (Jane Doe)".
+func (i RSSItem) authorName() string {
+	if creator := strings.TrimSpace(i.Creator); creator != "" {
+		return creator
+	}
+	author := strings.TrimSpace(i.Author)
+	if start := strings.Index(author, "("); start != -1 && strings.HasSuffix(author, ")") {
+		if name := strings.TrimSpace(author[start+1 : len(author)-1]); name != "" {
+			return name
+		}
+	}
+	return author
+}
+
+// RDF is an RSS 1.0 document. Unlike RSS 2.0 its items are siblings of the
+// channel rather than inside it, and dates and authors come from Dublin
+// Core.
+type RDF struct {
+	Channel RDFChannel `xml:"channel"`
+	Items   []RDFItem  `xml:"item"`
+}
+
+type RDFChannel struct {
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	// The syndication module started out as an RSS 1.0 module
+	UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+}
+
+type RDFItem struct {
+	// About is the item's URI, usually its link
+	About       string   `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	Description string   `xml:"description"`
+	Content     string   `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date        string   `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator     string   `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subjects    []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+}
+
+type Atom struct {
+	Base        string       `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title       AtomText     `xml:"title"`
+	Subtitle    AtomText     `xml:"subtitle"`
+	Items       []AtomItem   `xml:"entry"`
+	LastUpdated string       `xml:"updated"`
+	Authors     []AtomPerson `xml:"author"`
+	Links       []AtomLink   `xml:"link"`
+}
+
+type AtomItem struct {
+	Base      string     `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	ID        string     `xml:"id"`
+	Title     AtomText   `xml:"title"`
+	Links     []AtomLink `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   AtomText   `xml:"summary"`
+	Content   AtomText   `xml:"content"`
+	// Entries without authors inherit the feed's
+	Authors    []AtomPerson   `xml:"author"`
+	Categories []AtomCategory `xml:"category"`
+}
+
+// AtomCategory is identified by its term; the label is only for display.
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+// authorNames joins the names of people, skipping empty ones.
+func authorNames(people []AtomPerson) string {
+	var names []string
+	for _, person := range people {
+		if name := strings.TrimSpace(person.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// AtomText is an Atom text construct, whose type attribute says whether the
+// body is plain text, escaped HTML or inline XHTML.
+type AtomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// PlainText returns the construct's content with any markup removed and
+// HTML entities decoded, as used for titles and summaries.
+func (t AtomText) PlainText() string {
+	switch t.Type {
+	case "html":
+		return stripTags(t.Text)
+	case "xhtml":
+		return stripTags(t.Inner)
+	default:
+		return t.Text
+	}
+}
+
+// Markdown returns the construct's content converted to markdown, as used
+// for the content shown in the reading pane.
+func (t AtomText) Markdown() string {
+	switch t.Type {
+	case "html":
+		return htmlToMarkdown(t.Text)
+	case "xhtml":
+		return htmlToMarkdown(t.Inner)
+	default:
+		return truncateRunes(strings.TrimSpace(t.Text), maxContentLength)
+	}
+}
+
+type AtomLink struct {
+	Base string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Href string `xml:"href,attr"`
+	// Rel is "alternate" when missing
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// link returns the entry's alternate link, preferring an HTML page over
+// alternates in other formats, or its first link when none is marked as
+// such. Entries commonly list self, edit and enclosure links next to it.
+func (i AtomItem) link() string {
+	var alternate, first string
+	for _, link := range i.Links {
+		href := link.href()
+		if href == "" {
+			continue
+		}
+		if first == "" {
+			first = href
+		}
+		if link.Rel != "" && link.Rel != "alternate" {
+			continue
+		}
+		mediaType, _, _ := strings.Cut(link.Type, ";")
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "", "text/html", "application/xhtml+xml":
+			return href
+		}
+		if alternate == "" {
+			alternate = href
+		}
+	}
+	if alternate != "" {
+		return alternate
+	}
+	return first
+}
+
+// enclosure returns the entry's first link with rel="enclosure".
+func (i AtomItem) enclosure() Enclosure {
+	for _, link := range i.Links {
+		if link.Rel == "enclosure" && strings.TrimSpace(link.Href) != "" {
+			return Enclosure{URL: link.href(), Type: link.Type, Length: parseLength(link.Length)}
+		}
+	}
+	return Enclosure{}
+}
+
+// href returns the link's URL, resolved against its own xml:base
+func (l AtomLink) href() string {
+	return resolveURL(l.Base, strings.TrimSpace(l.Href))
+}
+
+// JSONFeed is a JSON Feed document, see https://jsonfeed.org/version/1.1
+type JSONFeed struct {
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	Items       []JSONFeedItem   `json:"items"`
+	Authors     []JSONFeedAuthor `json:"authors"`
+	// Author is the single author of JSON Feed 1.0, replaced by Authors
+	Author *JSONFeedAuthor `json:"author"`
+	// NextURL points at the page with older items of a paginated feed
+	NextURL string `json:"next_url"`
+}
+
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// jsonFeedAuthorNames handles both the 1.1 authors list and the 1.0 author.
+func jsonFeedAuthorNames(authors []JSONFeedAuthor, author *JSONFeedAuthor) string {
+	if len(authors) == 0 && author != nil {
+		authors = []JSONFeedAuthor{*author}
+	}
+	var names []string
+	for _, author := range authors {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+type JSONFeedItem struct {
+	ID            string `json:"id"`
+	URL           string `json:"url"`
+	Title         string `json:"title"`
+	ContentHTML   string `json:"content_html"`
+	ContentText   string `json:"content_text"`
+	Summary       string `json:"summary"`
+	DatePublished string `json:"date_published"`
+	DateModified  string `json:"date_modified"`
+	// Items without authors inherit the feed's
+	Authors     []JSONFeedAuthor     `json:"authors"`
+	Author      *JSONFeedAuthor      `json:"author"`
+	Tags        []string             `json:"tags"`
+	Attachments []JSONFeedAttachment `json:"attachments"`
+	Image       string               `json:"image"`
+}
+
+// markdown returns the item's content as markdown, converting it from HTML
+// unless there is a plain-text version
+func (i JSONFeedItem) markdown() string {
+	if text := strings.TrimSpace(i.ContentText); text != "" {
+		return truncateRunes(text, maxContentLength)
+	}
+	return htmlToMarkdown(i.ContentHTML)
+}
+
+type JSONFeedAttachment struct {
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type"`
+	// SizeInBytes is a float so a feed writing 1.2e7 doesn't fail to parse
+	SizeInBytes       float64 `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+// enclosure returns the item's first attachment that has a URL.
+func (i JSONFeedItem) enclosure() Enclosure {
+	for _, attachment := range i.Attachments {
+		if url := strings.TrimSpace(attachment.URL); url != "" {
+			return Enclosure{
+				URL:      url,
+				Type:     attachment.MimeType,
+				Length:   max(int64(attachment.SizeInBytes), 0),
+				Duration: max(time.Duration(attachment.DurationInSeconds*float64(time.Second)).Round(time.Second), 0),
+			}
+		}
+	}
+	return Enclosure{}
+}
+
+type NormalizedItem struct {
+	// GUID identifies the item within its feed. It may be empty, in which
+	// case the URL is used instead.
+	GUID      string
+	Title     string
+	URL       string
+	Published string
+	// Summary is a plain-text preview of the item, at most maxSummaryLength
+	// runes long.
+	Summary string
+	// Author names whoever wrote the item, joined by commas when there are
+	// several. It is empty when the feed doesn't say.
+	Author string
+	// Categories are the item's tags, trimmed and without duplicates
+	Categories []string
+	// Enclosure is media attached to the item, with an empty URL when there
+	// is none.
+	Enclosure Enclosure
+	// Image is the URL of the item's artwork, such as a podcast episode's
+	// cover, or empty
+	Image string
+	// Content is the full text of the item as markdown, at most
+	// maxContentLength runes long, or empty when the feed only links to it
+	Content string
+}
+
+// Enclosure is a media file attached to an item, such as a podcast episode.
+type Enclosure struct {
+	URL string
+	// Type is the MIME type, e.g. "audio/mpeg", and may be empty
+	Type string
+	// Length is the size in bytes, or 0 when unknown
+	Length int64
+	// Duration is how long the media plays, or 0 when unknown
+	Duration time.Duration
+}
+
+const maxSummaryLength = 500
+
+// parsedFeed is what a feed document holds: its items and what it says
+// about the feed itself
+type parsedFeed struct {
+	title         string
+	description   string
+	lastUpdatedAt string
+	// interval is how often the feed asks to be polled, zero if it doesn't
+	interval time.Duration
+	items    []NormalizedItem
+}
+
+// feedText tidies a feed's title or description for storing on one line
+func feedText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func parseFeed[T RawFeed](body []byte, feed *T) error {
+	if jsonFeed, ok := any(feed).(*JSONFeed); ok {
+		return json.Unmarshal(body, jsonFeed)
+	}
+	return decodeXMLFeed(body, feed)
+}
+
+func getRSSFeed(body []byte) (parsedFeed, error) {
+	var rss RSS
+	err := parseFeed(body, &rss)
+	if err != nil {
+		return parsedFeed{}, fmt.Errorf("error parsing XML: %w", err)
+	}
+
+	items := make([]NormalizedItem, len(rss.Channel.Items))
+	for i, item := range rss.Channel.Items {
+		items[i] = NormalizedItem{
+			GUID:       strings.TrimSpace(item.GUID),
+			Title:      item.Title,
+			URL:        item.Link,
+			Published:  item.Published,
+			Summary:    summarize(stripTags(cmp.Or(item.Description, item.ITunesSummary))),
+			Author:     item.authorName(),
+			Categories: normalizeCategories(item.Categories),
+			Enclosure:  item.enclosure(),
+			Image:      strings.TrimSpace(cmp.Or(item.Image.Href, rss.Channel.Image.Href)),
+			Content:    htmlToMarkdown(cmp.Or(item.Content, item.Description, item.ITunesSummary)),
+		}
+	}
+	return parsedFeed{
+		title:         feedText(rss.Channel.Title),
+		description:   feedText(stripTags(rss.Channel.Description)),
+		lastUpdatedAt: rss.Channel.LastUpdated,
+		interval:      rss.Channel.updateInterval(),
+		items:         items,
+	}, nil
+}
+
+func getRDFFeed(body []byte) (parsedFeed, error) {
+	var rdf RDF
+	err := parseFeed(body, &rdf)
+	if err != nil {
+		return parsedFeed{}, fmt.Errorf("error parsing XML: %w", err)
+	}
+
+	items := make([]NormalizedItem, len(rdf.Items))
+	for i, item := range rdf.Items {
+		items[i] = NormalizedItem{
+			GUID:       strings.TrimSpace(item.About),
+			Title:      item.Title,
+			URL:        strings.TrimSpace(item.Link),
+			Published:  item.Date,
+			Summary:    summarize(stripTags(item.Description)),
+			Author:     strings.TrimSpace(item.Creator),
+			Categories: normalizeCategories(item.Subjects),
+			Content:    htmlToMarkdown(cmp.Or(item.Content, item.Description)),
+		}
+	}
+
+	interval := Channel{
+		UpdatePeriod:    rdf.Channel.UpdatePeriod,
+		UpdateFrequency: rdf.Channel.UpdateFrequency,
+	}.updateInterval()
+	return parsedFeed{
+		title:         feedText(rdf.Channel.Title),
+		description:   feedText(stripTags(rdf.Channel.Description)),
+		lastUpdatedAt: rdf.Channel.Date,
+		interval:      interval,
+		items:         items,
+	}, nil
+}
+
+func getAtomFeed(body []byte) (parsedFeed, error) {
+	var atom Atom
+	err := parseFeed(body, &atom)
+	if err != nil {
+		return parsedFeed{}, fmt.Errorf("error parsing XML: %w", err)
+	}
+
+	feedAuthor := authorNames(atom.Authors)
+	items := make([]NormalizedItem, len(atom.Items))
+	for i, item := range atom.Items {
+		// Links relative to xml:base stay relative to the feed's URL when
+		// the base itself is relative, fetchFeed resolves those
+		base := atom.Base
+		if item.Base != "" {
+			base = resolveURL(base, item.Base)
+		}
+		enclosure := item.enclosure()
+		enclosure.URL = resolveURL(base, enclosure.URL)
+
+		// Prefer Published over Updated, but use Updated as fallback
+		dateStr := item.Published
+		if dateStr == "" {
+			dateStr = item.Updated
+		}
+		// Prefer the short summary, but fall back to the full content
+		summary := item.Summary.PlainText()
+		if strings.TrimSpace(summary) == "" {
+			summary = item.Content.PlainText()
+		}
+
+		content := item.Content.Markdown()
+		if content == "" {
+			content = item.Summary.Markdown()
+		}
+
+		items[i] = NormalizedItem{
+			GUID:       strings.TrimSpace(item.ID),
+			Title:      item.Title.PlainText(),
+			URL:        resolveURL(base, item.link()),
+			Published:  dateStr,
+			Summary:    summarize(summary),
+			Author:     cmp.Or(authorNames(item.Authors), feedAuthor),
+			Categories: normalizeCategories(atomTerms(item.Categories)),
+			Enclosure:  enclosure,
+			Content:    content,
+		}
+	}
+	return parsedFeed{
+		title:         feedText(atom.Title.PlainText()),
+		description:   feedText(atom.Subtitle.PlainText()),
+		lastUpdatedAt: atom.LastUpdated,
+		items:         items,
+	}, nil
+}
+
+func getJSONFeed(body []byte) (parsedFeed, error) {
+	var jsonFeed JSONFeed
+	err := parseFeed(body, &jsonFeed)
+	if err != nil {
+		return parsedFeed{}, fmt.Errorf("error parsing JSON: %w", err)
+	}
+
+	// JSON Feed has no feed-level timestamp, so use the newest item instead
+	var lastUpdated time.Time
+	feedAuthor := jsonFeedAuthorNames(jsonFeed.Authors, jsonFeed.Author)
+	items := make([]NormalizedItem, len(jsonFeed.Items))
+	for i, item := range jsonFeed.Items {
+		dateStr := item.DatePublished
+		if dateStr == "" {
+			dateStr = item.DateModified
+		}
+
+		for _, candidate := range []string{item.DatePublished, item.DateModified} {
+			if t, _, err := parseDate(candidate); err == nil && t.After(lastUpdated) {
+				lastUpdated = t
+			}
+		}
+
+		content := item.ContentText
+		if content == "" {
+			content = stripTags(item.ContentHTML)
+		}
+
+		summary := item.Summary
+		if summary == "" {
+			summary = content
+		}
+
+		// Titles are optional in JSON Feed, so fall back to the content
+		title := item.Title
+		if title == "" {
+			title = content
+		}
+
+		items[i] = NormalizedItem{
+			GUID:       strings.TrimSpace(item.ID),
+			Title:      truncateRunes(strings.Join(strings.Fields(title), " "), 100),
+			URL:        item.URL,
+			Published:  dateStr,
+			Summary:    summarize(summary),
+			Author:     cmp.Or(jsonFeedAuthorNames(item.Authors, item.Author), feedAuthor),
+			Categories: normalizeCategories(item.Tags),
+			Enclosure:  item.enclosure(),
+			Image:      strings.TrimSpace(item.Image),
+			Content:    item.markdown(),
+		}
+	}
+
+	parsed := parsedFeed{
+		title:       feedText(jsonFeed.Title),
+		description: feedText(jsonFeed.Description),
+		items:       items,
+	}
+	if !lastUpdated.IsZero() {
+		parsed.lastUpdatedAt = lastUpdated.Format(time.RFC3339)
+	}
+	return parsed, nil
+}
+
+func atomTerms(categories []AtomCategory) []string {
+	terms := make([]string, len(categories))
+	for i, category := range categories {
+		terms[i] = category.Term
+	}
+	return terms
+}
+
+// normalizeCategories trims categories and drops empty and duplicate ones.
+// Commas are replaced since categories are stored comma-separated.
+func normalizeCategories(categories []string) []string {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, category := range categories {
+		category = strings.Join(strings.Fields(strings.ReplaceAll(category, ",", " ")), " ")
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		normalized = append(normalized, category)
+	}
+	return normalized
+}
+
+// stripTags reduces an HTML fragment to its plain text content.
+func stripTags(fragment string) string {
+	return parseHTMLFragment(fragment).text()
+}
+
+// summarize collapses whitespace in text and bounds it to maxSummaryLength.
+func summarize(text string) string {
+	return truncateRunes(strings.Join(strings.Fields(text), " "), maxSummaryLength)
+}
+
+// truncateRunes shortens s to at most limit runes, marking the cut with "…".
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
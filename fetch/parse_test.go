@@ -0,0 +1,87 @@
+package fetch
+
+import "testing"
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		fragment string
+		want     string
+	}{
+		{"<p>Hello <b>world</b></p>", "Hello world"},
+		{"5 < 6 and more text", "5 < 6 and more text"},
+		{"a <3 b, <i>still</i> here", "a <3 b, still here"},
+		{"Fish &amp; chips<br>and peas", "Fish & chips and peas"},
+		{"<script>if (a < b) {}</script>Text", "Text"},
+		{"<p>Unclosed <em>tags", "Unclosed tags"},
+		{"<title>Not a head</title> body", "Not a head body"},
+	}
+	for _, test := range tests {
+		if got := stripTags(test.fragment); got != test.want {
+			t.Errorf("stripTags(%q) = %q, want %q", test.fragment, got, test.want)
+		}
+	}
+}
+
+func TestAtomEntryLink(t *testing.T) {
+	entry := func(links string) string {
+		return `<?xml version="1.0"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><entry><id>1</id><title>Post</title>` + links + `</entry></feed>`
+	}
+	tests := []struct {
+		name  string
+		links string
+		want  string
+	}{
+		{
+			"alternate after self and enclosure",
+			`<link rel="self" href="https://example.com/entry.atom"/>
+			<link rel="enclosure" type="audio/mpeg" href="https://example.com/ep.mp3"/>
+			<link rel="alternate" type="text/html" href="https://example.com/post"/>`,
+			"https://example.com/post",
+		},
+		{
+			"missing rel is alternate",
+			`<link rel="edit" href="https://example.com/edit"/>
+			<link href="https://example.com/post"/>`,
+			"https://example.com/post",
+		},
+		{
+			"HTML alternate over other formats",
+			`<link rel="alternate" type="application/pdf" href="https://example.com/post.pdf"/>
+			<link rel="alternate" type="text/html; charset=utf-8" href="https://example.com/post"/>`,
+			"https://example.com/post",
+		},
+		{
+			"alternate in another format over related",
+			`<link rel="related" href="https://other.example/"/>
+			<link rel="alternate" type="application/pdf" href="https://example.com/post.pdf"/>`,
+			"https://example.com/post.pdf",
+		},
+		{
+			"first link without an alternate",
+			`<link rel="related" href="https://other.example/"/>
+			<link rel="enclosure" href="https://example.com/ep.mp3"/>`,
+			"https://other.example/",
+		},
+		{
+			"empty href skipped",
+			`<link rel="alternate" href=""/>
+			<link rel="alternate" href="https://example.com/post"/>`,
+			"https://example.com/post",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			feed, err := getAtomFeed([]byte(entry(test.links)))
+			if err != nil {
+				t.Fatalf("getAtomFeed: %v", err)
+			}
+			if len(feed.items) != 1 {
+				t.Fatalf("got %d items, want 1", len(feed.items))
+			}
+			if got := feed.items[0].URL; got != test.want {
+				t.Errorf("URL = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
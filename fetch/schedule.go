@@ -0,0 +1,71 @@
+package fetch
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// maxSuggestedInterval caps the polling interval a feed can ask for, so a
+// feed with an overly cautious hint is still checked daily
+const maxSuggestedInterval = 24 * time.Hour
+
+// syndicationPeriods are the values of <sy:updatePeriod>
+var syndicationPeriods = map[string]time.Duration{
+	"hourly":  time.Hour,
+	"daily":   24 * time.Hour,
+	"weekly":  7 * 24 * time.Hour,
+	"monthly": 30 * 24 * time.Hour,
+	"yearly":  365 * 24 * time.Hour,
+}
+
+// updateInterval returns how often the channel asks to be polled, taking
+// the longer of <ttl> and the syndication module's period, or zero when it
+// gives no hint
+func (c Channel) updateInterval() time.Duration {
+	var interval time.Duration
+	if minutes, err := strconv.Atoi(strings.TrimSpace(c.TTL)); err == nil && minutes > 0 {
+		interval = time.Duration(minutes) * time.Minute
+	}
+
+	if period, ok := syndicationPeriods[strings.ToLower(strings.TrimSpace(c.UpdatePeriod))]; ok {
+		// The frequency is how many updates happen per period
+		frequency, err := strconv.Atoi(strings.TrimSpace(c.UpdateFrequency))
+		if err != nil || frequency <= 0 {
+			frequency = 1
+		}
+		interval = max(interval, period/time.Duration(frequency))
+	}
+
+	return min(interval, maxSuggestedInterval)
+}
+
+// RefreshInterval returns the minimum time between fetches of feed: the
+// interval set by the user, or else the one the feed asked for. Zero means
+// it is fetched on every run.
+func RefreshInterval(feed database.Feed) time.Duration {
+	if feed.RefreshIntervalSeconds.Valid {
+		return time.Duration(feed.RefreshIntervalSeconds.Int64) * time.Second
+	}
+	if feed.SuggestedIntervalSeconds.Valid {
+		return time.Duration(feed.SuggestedIntervalSeconds.Int64) * time.Second
+	}
+	return 0
+}
+
+// dueFeeds drops the feeds that were checked more recently than their
+// refresh interval, or minInterval when that is longer
+func dueFeeds(feeds []database.Feed, minInterval time.Duration, now time.Time) []database.Feed {
+	var due []database.Feed
+	for _, feed := range feeds {
+		interval := max(minInterval, RefreshInterval(feed))
+		checkedAt, err := time.Parse(time.RFC3339, feed.LastCheckedAt.String)
+		if interval > 0 && err == nil && now.Sub(checkedAt) < interval {
+			continue
+		}
+		due = append(due, feed)
+	}
+	return due
+}
@@ -0,0 +1,442 @@
+package fetch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// storeFeed writes the outcome of a fetch to the database and logs it. It
+// returns how many new posts were stored and the error that kept the feed
+// from being fetched or its posts from being stored, if any; failures while
+// recording the outcome are only logged.
+func storeFeed(ctx context.Context, queries *database.Queries, result fetchResult, log *slog.Logger) (int64, error) {
+	feed := result.feed
+	log = log.With("feed", feed.Name)
+	checkedAt := time.Now().Format(time.RFC3339)
+
+	if errors.Is(result.err, errNotModified) {
+		clearFeedError(ctx, queries, feed, log)
+		markFeedChecked(ctx, queries, feed.ID, checkedAt, log)
+		log.Info("Feed not modified", "status", "not-modified")
+		return 0, nil
+	}
+	if result.err != nil {
+		recordFeedError(ctx, queries, feed.ID, result.err, checkedAt, log)
+		// A failing feed waits for its next turn like any other, RefreshFailed
+		// fetches it sooner. A cancelled fetch never reached it, though.
+		if !errors.Is(result.err, errFetchCancelled) {
+			markFeedChecked(ctx, queries, feed.ID, checkedAt, log)
+		}
+
+		switch {
+		case errors.Is(result.err, ErrFeedAuth):
+			log.Warn("Feed was refused, check its credentials", "status", "refused", "err", result.err)
+		case errors.Is(result.err, ErrFeedGone):
+			log.Warn("Feed is gone, it may have moved or can be removed", "status", "gone", "err", result.err)
+		case errors.Is(result.err, errFetchCancelled):
+			log.Warn("Gave up fetching feed", "status", "cancelled", "err", result.err)
+		default:
+			log.Warn("Failed fetching feed", "status", "failed", "url", feed.Url, "err", result.err)
+		}
+		return 0, result.err
+	}
+
+	// Store the posts and the feed state that goes with them in one
+	// transaction, so a failure can't leave the cache headers claiming
+	// posts were stored when they weren't
+	var counts storeCounts
+	err := queries.InTx(ctx, func(queries *database.Queries) error {
+		var err error
+		counts, err = storeItems(ctx, queries, result, checkedAt, log)
+		return err
+	})
+	if err != nil {
+		log.Error("Failed storing feed", "status", "failed", "err", err)
+		recordFeedError(ctx, queries, feed.ID, err, checkedAt, log)
+		markFeedChecked(ctx, queries, feed.ID, checkedAt, log)
+		return 0, err
+	}
+
+	clearFeedError(ctx, queries, feed, log)
+	markFeedChecked(ctx, queries, feed.ID, checkedAt, log)
+	log.Info("Fetched feed",
+		"status", "ok",
+		"items", len(result.items),
+		"new", counts.added,
+		"muted", counts.muted,
+		"duplicates", int64(len(result.items))-counts.added-counts.tooOld,
+		"too_old", counts.tooOld,
+	)
+	return counts.added, nil
+}
+
+// storeCounts tallies the posts of one fetch by what became of them
+type storeCounts struct {
+	// added is every new post, including the muted ones
+	added int64
+	// muted posts were stored already archived
+	muted int64
+	// tooOld posts were published before the feed's store_since cutoff
+	tooOld int64
+}
+
+// storeItems writes the posts of a successful fetch along with the feed's
+// date format, update time, cache headers and refresh hint, stopping at the
+// first failure. New posts whose title matches a mute pattern are stored
+// already archived.
+func storeItems(
+	ctx context.Context,
+	queries *database.Queries,
+	result fetchResult,
+	checkedAt string,
+	log *slog.Logger,
+) (storeCounts, error) {
+	feed := result.feed
+	lastUpdatedAt := result.lastUpdatedAt
+
+	var counts storeCounts
+	// Every layout that parsed a date, in the order they were first used,
+	// so the stored format can follow the bulk of the feed's dates
+	formatCounts := make(map[string]int)
+	var formats []string
+
+	// Posts without a usable date are placed at the feed's own update time,
+	// or failing that at the time they were fetched
+	estimatedDate := checkedAt
+	if parsedTime, _, err := parseDateWithFormat(lastUpdatedAt, feed.DateFormat); err == nil {
+		estimatedDate = parsedTime.Format(time.RFC3339)
+	}
+
+	mutes, err := loadMutes(ctx, queries, feed.ID, log)
+	if err != nil {
+		return counts, err
+	}
+	deletedGUIDs, err := queries.ListDeletedGUIDs(ctx, feed.ID)
+	if err != nil {
+		return counts, fmt.Errorf("failed listing deleted posts: %w", err)
+	}
+	deleted := make(map[string]bool, len(deletedGUIDs))
+	for _, guid := range deletedGUIDs {
+		deleted[guid] = true
+	}
+
+	var cutoff time.Time
+	if feed.StoreSince.Valid {
+		cutoff, err = time.Parse(time.RFC3339, feed.StoreSince.String)
+		if err != nil {
+			log.Warn("Ignoring invalid store_since", "store_since", feed.StoreSince.String, "err", err)
+		}
+	}
+
+	// Every GUID still in the feed, whether or not its post is stored
+	inFeed := make(map[string]bool, len(result.items))
+	for _, item := range result.items {
+		// Deduplicate on the GUID, falling back to the URL for feeds without one
+		guid := item.GUID
+		if guid == "" {
+			guid = item.URL
+		}
+		inFeed[guid] = true
+
+		// Keep posts whose date can't be parsed rather than losing them
+		unifiedDate := estimatedDate
+		dateEstimated := int64(1)
+		parsedTime, usedFormat, err := parseDateWithFormat(item.Published, feed.DateFormat)
+		if err == nil {
+			unifiedDate = parsedTime.Format(time.RFC3339)
+			dateEstimated = 0
+		} else if item.Published != "" {
+			log.Debug("Failed parsing post date, estimating it", "post", item.Title, "err", err)
+		}
+		if usedFormat != "" {
+			if formatCounts[usedFormat] == 0 {
+				formats = append(formats, usedFormat)
+			}
+			formatCounts[usedFormat]++
+		}
+
+		// Posts without a date of their own are kept, there's no telling
+		// how old they are
+		if dateEstimated == 0 && parsedTime.Before(cutoff) {
+			counts.tooOld++
+			log.Debug("Skipped post published before the cutoff", "post", item.Title, "published", unifiedDate)
+			continue
+		}
+
+		// Posts deleted for good count as already stored
+		if deleted[guid] {
+			log.Debug("Skipped deleted post", "post", item.Title, "guid", guid)
+			continue
+		}
+
+		// Muted posts are kept so they aren't seen as new on the next fetch
+		muted := isMuted(mutes, item.Title)
+		archived := int64(0)
+		if muted {
+			archived = 1
+		}
+
+		n, err := queries.CreatePost(ctx, database.CreatePostParams{
+			Title:       item.Title,
+			Url:         item.URL,
+			PublishedAt: unifiedDate,
+			FeedID:      feed.ID,
+			Guid:        guid,
+			Summary:     sql.NullString{String: item.Summary, Valid: item.Summary != ""},
+			Author:      sql.NullString{String: item.Author, Valid: item.Author != ""},
+			Categories: sql.NullString{
+				String: strings.Join(item.Categories, ","),
+				Valid:  len(item.Categories) > 0,
+			},
+			DateEstimated: sql.NullInt64{Int64: dateEstimated, Valid: true},
+			EnclosureUrl:  sql.NullString{String: item.Enclosure.URL, Valid: item.Enclosure.URL != ""},
+			EnclosureType: sql.NullString{String: item.Enclosure.Type, Valid: item.Enclosure.Type != ""},
+			EnclosureLength: sql.NullInt64{
+				Int64: item.Enclosure.Length,
+				Valid: item.Enclosure.Length > 0,
+			},
+			EnclosureDuration: sql.NullInt64{
+				Int64: int64(item.Enclosure.Duration / time.Second),
+				Valid: item.Enclosure.Duration > 0,
+			},
+			ImageUrl:   sql.NullString{String: item.Image, Valid: item.Image != ""},
+			Content:    sql.NullString{String: item.Content, Valid: item.Content != ""},
+			IsArchived: sql.NullInt64{Int64: archived, Valid: true},
+		})
+		if err != nil {
+			return counts, fmt.Errorf("failed writing post '%s': %w", item.Title, err)
+		}
+		// Posts already stored are ignored rather than inserted
+		if n == 0 {
+			log.Debug("Post already stored", "post", item.Title, "guid", guid)
+			continue
+		}
+		counts.added += n
+		if muted {
+			counts.muted += n
+		}
+		log.Debug("Stored post",
+			"post", item.Title,
+			"guid", guid,
+			"published", unifiedDate,
+			"estimated", dateEstimated == 1,
+			"muted", muted,
+		)
+	}
+
+	if err := forgetDeletedPosts(ctx, queries, feed.ID, deleted, inFeed); err != nil {
+		return counts, err
+	}
+
+	if result.feedType != "" && result.feedType != feed.FeedType {
+		err := queries.UpdateFeedType(
+			ctx,
+			database.UpdateFeedTypeParams{
+				FeedType: result.feedType,
+				ID:       feed.ID,
+			},
+		)
+		if err != nil {
+			return counts, fmt.Errorf("failed updating feed type: %w", err)
+		}
+		log.Info("Corrected feed type", "from", feed.FeedType, "to", result.feedType)
+	}
+
+	if err := updateFeedTitle(ctx, queries, feed, result.parsedFeed, log); err != nil {
+		return counts, err
+	}
+
+	// A layout given with add -date-format is kept even when the feed's
+	// dates stray from it
+	userSet := feed.DateFormatUserSet.Valid && feed.DateFormatUserSet.Int64 == 1
+	detectedFormat := mostCommonFormat(formats, formatCounts, feed.DateFormat.String)
+	if !userSet && detectedFormat != "" && detectedFormat != feed.DateFormat.String {
+		err := queries.UpdateFeedFormat(
+			ctx,
+			database.UpdateFeedFormatParams{
+				DateFormat: sql.NullString{String: detectedFormat, Valid: true},
+				ID:         feed.ID,
+			},
+		)
+		if err != nil {
+			return counts, fmt.Errorf("failed updating feed format: %w", err)
+		}
+		log.Debug("Stored feed date format",
+			"format", detectedFormat,
+			"previous", feed.DateFormat.String,
+			"items", formatCounts[detectedFormat],
+		)
+	}
+
+	if lastUpdatedAt != "" {
+		parsedTime, _, err := parseDateWithFormat(lastUpdatedAt, feed.DateFormat)
+		if err == nil {
+			lastUpdatedAt = parsedTime.Format(time.RFC3339)
+		}
+	}
+
+	err = queries.UpdateFeedDate(
+		ctx,
+		database.UpdateFeedDateParams{
+			LastUpdatedAt: sql.NullString{String: lastUpdatedAt, Valid: true},
+			ID:            feed.ID,
+		},
+	)
+	if err != nil {
+		return counts, fmt.Errorf("failed updating feed date: %w", err)
+	}
+
+	err = queries.UpdateFeedCacheHeaders(
+		ctx,
+		database.UpdateFeedCacheHeadersParams{
+			Etag:         sql.NullString{String: result.cache.ETag, Valid: result.cache.ETag != ""},
+			LastModified: sql.NullString{String: result.cache.LastModified, Valid: result.cache.LastModified != ""},
+			ID:           feed.ID,
+		},
+	)
+	if err != nil {
+		return counts, fmt.Errorf("failed updating feed cache headers: %w", err)
+	}
+	log.Debug("Stored feed state",
+		"last_updated", lastUpdatedAt,
+		"etag", result.cache.ETag,
+		"last_modified", result.cache.LastModified,
+	)
+
+	suggested := int64(result.interval / time.Second)
+	if suggested != feed.SuggestedIntervalSeconds.Int64 {
+		err = queries.UpdateFeedSuggestedInterval(
+			ctx,
+			database.UpdateFeedSuggestedIntervalParams{
+				SuggestedIntervalSeconds: sql.NullInt64{Int64: suggested, Valid: suggested > 0},
+				ID:                       feed.ID,
+			},
+		)
+		if err != nil {
+			return counts, fmt.Errorf("failed updating feed refresh interval: %w", err)
+		}
+		log.Debug("Stored feed refresh hint", "interval", result.interval)
+	}
+
+	return counts, nil
+}
+
+// recordFeedError keeps the latest fetch error on the feed so it can be
+// shown later, instead of only being printed during the run.
+// forgetDeletedPosts drops the GUIDs remembered for posts deleted for good
+// once they have left the feed, as they can't come back as new then. An
+// empty feed is more likely a glitch, so it keeps them all.
+func forgetDeletedPosts(
+	ctx context.Context,
+	queries *database.Queries,
+	feedID int64,
+	deleted map[string]bool,
+	inFeed map[string]bool,
+) error {
+	if len(inFeed) == 0 {
+		return nil
+	}
+	for guid := range deleted {
+		if inFeed[guid] {
+			continue
+		}
+		err := queries.DeleteDeletedPost(ctx, database.DeleteDeletedPostParams{FeedID: feedID, Guid: guid})
+		if err != nil {
+			return fmt.Errorf("failed forgetting deleted post: %w", err)
+		}
+	}
+	return nil
+}
+
+func recordFeedError(
+	ctx context.Context,
+	queries *database.Queries,
+	feedID int64,
+	fetchErr error,
+	failedAt string,
+	log *slog.Logger,
+) {
+	err := queries.UpdateFeedError(
+		ctx,
+		database.UpdateFeedErrorParams{
+			LastError:   sql.NullString{String: fetchErr.Error(), Valid: true},
+			LastErrorAt: sql.NullString{String: failedAt, Valid: true},
+			ID:          feedID,
+		},
+	)
+	if err != nil {
+		log.Error("Failed recording feed error", "err", err)
+	}
+}
+
+func clearFeedError(ctx context.Context, queries *database.Queries, feed database.Feed, log *slog.Logger) {
+	if !feed.LastError.Valid {
+		return
+	}
+	if err := queries.ClearFeedError(ctx, feed.ID); err != nil {
+		log.Error("Failed clearing feed error", "err", err)
+	}
+}
+
+func markFeedChecked(
+	ctx context.Context,
+	queries *database.Queries,
+	feedID int64,
+	checkedAt string,
+	log *slog.Logger,
+) {
+	err := queries.UpdateFeedCheckedAt(
+		ctx,
+		database.UpdateFeedCheckedAtParams{
+			LastCheckedAt: sql.NullString{String: checkedAt, Valid: true},
+			ID:            feedID,
+		},
+	)
+	if err != nil {
+		log.Error("Failed updating feed check time", "err", err)
+	}
+}
+
+// updateFeedTitle stores the title and description the feed gives itself
+// when they changed. The name follows the title when it was the previous
+// title, or the URL for feeds that had none, so a rename shows up without
+// overriding a name the user picked.
+func updateFeedTitle(
+	ctx context.Context,
+	queries *database.Queries,
+	feed database.Feed,
+	parsed parsedFeed,
+	log *slog.Logger,
+) error {
+	// Custom feeds are pages without a feed title, and an empty one is
+	// more likely a broken document than a rename
+	if parsed.title == "" ||
+		(parsed.title == feed.Title.String && parsed.description == feed.Description.String) {
+		return nil
+	}
+
+	name := feed.Name
+	if (feed.Title.Valid && feed.Name == feed.Title.String) || feed.Name == feed.Url {
+		name = parsed.title
+	}
+	err := queries.UpdateFeedTitle(ctx, database.UpdateFeedTitleParams{
+		Name:        name,
+		Title:       sql.NullString{String: parsed.title, Valid: true},
+		Description: sql.NullString{String: parsed.description, Valid: parsed.description != ""},
+		ID:          feed.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed updating feed title: %w", err)
+	}
+	if name != feed.Name {
+		log.Info("Renamed feed to its new title", "name", name)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package fetch
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+
+	"golang.org/x/net/html/charset"
+)
+
+// voidElements are HTML elements that feeds embed unescaped and never close.
+// HTMLAutoClose can't be used since it includes link, which RSS relies on.
+var voidElements = []string{"br", "hr", "img", "input", "wbr", "area", "embed", "source"}
+
+// newXMLDecoder returns a decoder that accepts what feeds actually serve
+// rather than only well-formed XML: HTML entities, stray ampersands,
+// unclosed void elements and encodings other than UTF-8.
+func newXMLDecoder(body []byte) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.Strict = false
+	decoder.AutoClose = voidElements
+	decoder.Entity = xml.HTMLEntity
+	// Handles Latin-1, Windows-1252 and every other encoding a feed may declare
+	decoder.CharsetReader = charset.NewReaderLabel
+	return decoder
+}
+
+// decodeXMLFeed decodes body into feed, a *RSS, *RDF or *Atom. A syntax
+// error is ignored when the items before it were decoded, so a truncated or
+// broken tail doesn't cost the whole feed.
+func decodeXMLFeed(body []byte, feed any) error {
+	err := newXMLDecoder(body).Decode(feed)
+
+	var syntaxErr *xml.SyntaxError
+	if err == nil || !(errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF)) {
+		return err
+	}
+
+	switch feed := feed.(type) {
+	case *RSS:
+		if len(feed.Channel.Items) > 0 {
+			return nil
+		}
+	case *RDF:
+		if len(feed.Items) > 0 {
+			return nil
+		}
+	case *Atom:
+		if len(feed.Items) > 0 {
+			return nil
+		}
+	}
+	return err
+}
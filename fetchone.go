@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
+)
+
+// fetchOne implements `feeder fetch -feed <url-or-name>`, fetching a single
+// feed, enabled or not, with every step logged so a feed that doesn't
+// update can be debugged without fetching the rest
+func fetchOne(ctx context.Context, queries *database.Queries, opts fetch.Options, target string) error {
+	feed, err := findFeed(ctx, queries, target)
+	if err != nil {
+		return err
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	if opts.DryRun {
+		logger = logger.With("dry_run", true)
+	}
+	opts.Logger = logger
+
+	added, fetchErr := fetch.FetchAndStore(ctx, queries, feed, opts)
+
+	// Read the feed back for the type and date format the fetch detected
+	feed, err = queries.GetFeedByURL(ctx, feed.Url)
+	if err != nil {
+		return fmt.Errorf("failed to read feed: %w", err)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Feed\t%s\n", feed.Name)
+	fmt.Fprintf(tw, "URL\t%s\n", feed.Url)
+	fmt.Fprintf(tw, "Type\t%s\n", feed.FeedType)
+	dateFormat := orNone(feed.DateFormat.String)
+	if feed.DateFormatUserSet.Valid && feed.DateFormatUserSet.Int64 == 1 {
+		dateFormat += " (set with -date-format)"
+	}
+	fmt.Fprintf(tw, "Date format\t%s\n", dateFormat)
+	fmt.Fprintf(tw, "New posts\t%d\n", added)
+	if fetchErr != nil {
+		fmt.Fprintf(tw, "Error\t%s\n", fetchErr)
+	}
+	tw.Flush()
+	return fetchErr
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
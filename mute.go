@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
+)
+
+// mutePosts implements `feeder mute [-feed <url-or-name>] [pattern]`. New
+// posts whose title matches the pattern are stored already archived; without
+// a pattern the existing mutes are listed.
+func mutePosts(ctx context.Context, queries *database.Queries, args []string) error {
+	fs := flag.NewFlagSet("mute", flag.ExitOnError)
+	feedTarget := fs.String("feed", "", "only mute posts of this feed, given by `url-or-name`")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder mute [-feed <url-or-name>] [pattern]")
+		fmt.Fprint(fs.Output(), "\nThe pattern is a case-insensitive regular expression matched against post\ntitles, e.g. \"sponsored|hiring\". Without one, mutes are listed.\n\n")
+		fs.PrintDefaults()
+	}
+	positional := parseInterspersed(fs, args)
+	if len(positional) > 1 {
+		fs.Usage()
+		return errors.New("mute expects one pattern, quote it if it has spaces")
+	}
+	if len(positional) == 0 {
+		return listMutes(ctx, queries)
+	}
+	pattern := positional[0]
+
+	if _, err := fetch.CompileMute(pattern); err != nil {
+		return err
+	}
+
+	var feedID sql.NullInt64
+	scope := "all feeds"
+	if *feedTarget != "" {
+		feed, err := findFeed(ctx, queries, *feedTarget)
+		if err != nil {
+			return err
+		}
+		feedID = sql.NullInt64{Int64: feed.ID, Valid: true}
+		scope = fmt.Sprintf("%q", feed.Name)
+	}
+
+	err := queries.CreateMute(ctx, database.CreateMuteParams{Pattern: pattern, FeedID: feedID})
+	if err != nil {
+		return fmt.Errorf("failed to add mute: %w", err)
+	}
+	fmt.Printf("Muted %q in %s, matching posts fetched from now on are archived\n", pattern, scope)
+	return nil
+}
+
+func listMutes(ctx context.Context, queries *database.Queries) error {
+	mutes, err := queries.ListMutes(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list mutes: %w", err)
+	}
+	if len(mutes) == 0 {
+		fmt.Println("Nothing is muted")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPATTERN\tFEED")
+	for _, mute := range mutes {
+		feed := "all"
+		if mute.FeedID.Valid {
+			feed = mute.FeedName.String
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", mute.ID, mute.Pattern, feed)
+	}
+	return w.Flush()
+}
+
+// unmutePosts implements `feeder unmute <id>`, with the ID `feeder mute`
+// lists. Posts archived by the mute stay archived.
+func unmutePosts(ctx context.Context, queries *database.Queries, args []string) error {
+	fs := flag.NewFlagSet("unmute", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder unmute <id>")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("unmute expects the ID of a mute")
+	}
+	id, err := strconv.ParseInt(fs.Arg(0), 10, 64)
+	if err != nil {
+		return fmt.Errorf("unmute expects the ID of a mute, got %q", fs.Arg(0))
+	}
+
+	removed, err := queries.DeleteMute(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to remove mute: %w", err)
+	}
+	if removed == 0 {
+		return fmt.Errorf("no mute with ID %d", id)
+	}
+	fmt.Printf("Removed mute %d\n", id)
+	return nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
+)
+
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    OPMLHead `xml:"head"`
+	Body    OPMLBody `xml:"body"`
+}
+
+type OPMLHead struct {
+	Title string `xml:"title"`
+}
+
+type OPMLBody struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// flattenOutlines collects every outline that points at a feed, descending
+// into folders.
+func flattenOutlines(outlines []Outline) []Outline {
+	var feeds []Outline
+	for _, outline := range outlines {
+		if outline.XMLURL != "" {
+			feeds = append(feeds, outline)
+		}
+		feeds = append(feeds, flattenOutlines(outline.Outlines)...)
+	}
+	return feeds
+}
+
+// importOPML implements `feeder import [-format txt] <file>`, reading OPML
+// unless the file is a plain list of URLs.
+func importOPML(ctx context.Context, queries *database.Queries, opts fetch.Options, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	format := fs.String("format", "opml", "format of the file: opml, or txt for one feed URL per line")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder import [-format opml|txt] <file>")
+		fs.PrintDefaults()
+	}
+	positional := parseInterspersed(fs, args)
+	if len(positional) != 1 {
+		fs.Usage()
+		return errors.New("import expects a file")
+	}
+	switch *format {
+	case "opml":
+	case "txt":
+		return importURLList(ctx, queries, opts, positional[0])
+	default:
+		return fmt.Errorf("unknown import format %q, expected opml or txt", *format)
+	}
+
+	outlines, err := readOPML(positional[0])
+	if err != nil {
+		return err
+	}
+
+	added, skipped := 0, 0
+	for _, outline := range outlines {
+		subscribed, err := isSubscribed(ctx, queries, outline.XMLURL)
+		if err != nil {
+			return err
+		}
+		if subscribed {
+			skipped++
+			continue
+		}
+
+		// The outline may point at a page or an old address of a feed that
+		// is already subscribed to
+		detected := detectOutline(ctx, opts, outline)
+		if detected.URL != outline.XMLURL {
+			if subscribed, err := isSubscribed(ctx, queries, detected.URL); err != nil {
+				return err
+			} else if subscribed {
+				skipped++
+				continue
+			}
+		}
+
+		err = queries.CreateFeed(ctx, outlineFeedParams(outline, detected))
+		if err != nil {
+			slog.Warn("Failed adding feed", "url", outline.XMLURL, "err", err)
+			skipped++
+			continue
+		}
+		added++
+	}
+
+	fmt.Printf("Imported %d feeds, skipped %d\n", added, skipped)
+	return nil
+}
+
+// readOPML returns the feed outlines of an OPML file
+func readOPML(path string) ([]Outline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var opml OPML
+	if err := xml.Unmarshal(data, &opml); err != nil {
+		return nil, fmt.Errorf("error parsing OPML %s: %w", path, err)
+	}
+	return flattenOutlines(opml.Body.Outlines), nil
+}
+
+// detectOutline finds the type, title and actual URL of the feed an outline
+// points at. OPML readers write type="rss" for every kind of feed, so the
+// attribute is only trusted when the feed can't be reached.
+func detectOutline(ctx context.Context, opts fetch.Options, outline Outline) fetch.DetectedFeed {
+	detected, err := fetch.Detect(ctx, opts, outline.XMLURL, fetch.Auth{})
+	if err != nil {
+		slog.Warn("Couldn't detect feed type, assuming it from OPML", "url", outline.XMLURL, "err", err)
+		return fetch.DetectedFeed{URL: outline.XMLURL, Type: opmlFeedType(outline.Type)}
+	}
+	return detected
+}
+
+// outlineFeedParams names the feed after the outline, falling back to the
+// feed's own title and then its URL
+func outlineFeedParams(outline Outline, detected fetch.DetectedFeed) database.CreateFeedParams {
+	name := cmp.Or(outline.Title, outline.Text, detected.Title, outline.XMLURL)
+	return database.CreateFeedParams{
+		Name:     name,
+		Url:      detected.URL,
+		FeedType: detected.Type,
+	}
+}
+
+func opmlFeedType(outlineType string) string {
+	switch outlineType {
+	case "rdf", "atom", "json":
+		return outlineType
+	default:
+		return "rss"
+	}
+}
+
+// exportOPML implements `feeder export [file.opml]`, writing to stdout when no
+// file is given.
+func exportOPML(ctx context.Context, queries *database.Queries, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder export [file.opml]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("export expects at most one file")
+	}
+
+	feeds, err := queries.ListFeeds(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list feeds: %w", err)
+	}
+
+	opml := OPML{
+		Version: "2.0",
+		Head:    OPMLHead{Title: "feeder subscriptions"},
+	}
+	for _, feed := range feeds {
+		opml.Body.Outlines = append(opml.Body.Outlines, Outline{
+			Text:   feed.Name,
+			Title:  feed.Name,
+			Type:   feed.FeedType,
+			XMLURL: feed.Url,
+		})
+	}
+
+	data, err := xml.MarshalIndent(opml, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding OPML: %w", err)
+	}
+	data = append([]byte(xml.Header), data...)
+	data = append(data, '\n')
+
+	if fs.NArg() == 0 {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(fs.Arg(0), data, 0o644)
+}
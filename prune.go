@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// prunePosts implements `feeder prune [-days N]`, deleting archived posts
+// that were published more than N days ago. Starred posts are always kept.
+func prunePosts(ctx context.Context, db *sql.DB, queries *database.Queries, defaultDays int, args []string) error {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	days := fs.Int("days", defaultDays, "delete archived posts older than this many days")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder prune [-days N]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("prune takes no arguments")
+	}
+
+	removed, err := prune(ctx, db, queries, *days)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pruned %d archived posts older than %d days\n", removed, *days)
+	return nil
+}
+
+// autoPrune prunes after a fetch when auto_prune is set, logging the result
+// instead of printing it
+func autoPrune(ctx context.Context, db *sql.DB, queries *database.Queries, days int) error {
+	removed, err := prune(ctx, db, queries, days)
+	if err != nil {
+		return err
+	}
+	slog.Info("Pruned archived posts", "removed", removed, "days", days)
+	return nil
+}
+
+// prune deletes archived, non-starred posts older than days and compacts
+// the database afterwards, returning how many posts were deleted. Their
+// GUIDs are kept, so posts still in their feed aren't fetched again.
+func prune(ctx context.Context, db *sql.DB, queries *database.Queries, days int) (int64, error) {
+	if days <= 0 {
+		return 0, fmt.Errorf("prune needs a positive number of days, got %d", days)
+	}
+
+	before := time.Now().AddDate(0, 0, -days).Format(time.RFC3339)
+	removed, err := queries.Prune(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune posts: %w", err)
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+	// Deleting rows leaves free pages behind; give them back to the disk
+	if _, err := db.ExecContext(ctx, "vacuum"); err != nil {
+		return removed, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+	return removed, nil
+}
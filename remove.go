@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// removeFeed implements `feeder remove <url-or-name>`, deleting the feed and
+// all of its posts in one transaction.
+func removeFeed(ctx context.Context, queries *database.Queries, args []string) error {
+	fs := flag.NewFlagSet("remove", flag.ExitOnError)
+	yes := fs.Bool("y", false, "remove without asking for confirmation")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder remove [-y] <url-or-name>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("remove expects a feed URL or name")
+	}
+
+	feed, err := findFeed(ctx, queries, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	if !*yes && !confirm(fmt.Sprintf("Remove feed %q (%s) and all its posts?", feed.Name, feed.Url)) {
+		fmt.Println("Aborted")
+		return nil
+	}
+
+	removed, err := queries.DeleteFeedCascade(ctx, feed.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed feed %q and %d posts\n", feed.Name, removed)
+	return nil
+}
+
+// findFeed looks up a single feed by URL or name, failing when the target
+// is ambiguous
+func findFeed(ctx context.Context, queries *database.Queries, target string) (database.Feed, error) {
+	feeds, err := queries.FindFeeds(ctx, target)
+	if err != nil {
+		return database.Feed{}, fmt.Errorf("failed to look up feed: %w", err)
+	}
+	switch len(feeds) {
+	case 0:
+		return database.Feed{}, fmt.Errorf("no feed matches %q", target)
+	case 1:
+		return feeds[0], nil
+	default:
+		return database.Feed{}, fmt.Errorf("%d feeds match %q, use the URL instead", len(feeds), target)
+	}
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
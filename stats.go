@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// showStats implements `feeder stats [-top N]`, printing totals of the feeds
+// and posts in the database and the feeds with the most posts
+func showStats(ctx context.Context, queries *database.Queries, dbPath string, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	top := fs.Int("top", 10, "number of feeds with the most posts to list")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder stats [-top N]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 0 {
+		fs.Usage()
+		return errors.New("stats takes no arguments")
+	}
+
+	feeds, err := queries.FeedStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count feeds: %w", err)
+	}
+	posts, err := queries.PostStats(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count posts: %w", err)
+	}
+	var topFeeds []database.TopFeedsByPostsRow
+	if *top > 0 {
+		if topFeeds, err = queries.TopFeedsByPosts(ctx, int64(*top)); err != nil {
+			return fmt.Errorf("failed to count posts per feed: %w", err)
+		}
+	}
+	// An in-memory or missing database has no size worth reporting
+	size := "unknown"
+	if info, err := os.Stat(dbPath); err == nil {
+		size = formatBytes(info.Size())
+	}
+
+	printStats(os.Stdout, feeds, posts, topFeeds, size)
+	return nil
+}
+
+func printStats(
+	w io.Writer,
+	feeds database.FeedStatsRow,
+	posts database.PostStatsRow,
+	topFeeds []database.TopFeedsByPostsRow,
+	size string,
+) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Feeds\t%d\n", feeds.Total)
+	fmt.Fprintf(tw, "Enabled\t%d\n", feeds.Enabled)
+	fmt.Fprintf(tw, "Disabled\t%d\n", feeds.Total-feeds.Enabled)
+	fmt.Fprintf(tw, "Failing\t%d\n", feeds.Failing)
+	fmt.Fprintf(tw, "Posts\t%d\n", posts.Total)
+	fmt.Fprintf(tw, "Unread\t%d\n", posts.Unread)
+	fmt.Fprintf(tw, "Starred\t%d\n", posts.Starred)
+	fmt.Fprintf(tw, "Archived\t%d\n", posts.Archived)
+	fmt.Fprintf(tw, "Oldest post\t%s\n", formatStatsDate(posts.Oldest))
+	fmt.Fprintf(tw, "Newest post\t%s\n", formatStatsDate(posts.Newest))
+	fmt.Fprintf(tw, "Database size\t%s\n", size)
+	tw.Flush()
+
+	if len(topFeeds) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "\nMost posts:")
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, feed := range topFeeds {
+		fmt.Fprintf(tw, "  %d\t%s\n", feed.Posts, feed.Name)
+	}
+	tw.Flush()
+}
+
+// formatStatsDate shortens a stored RFC 3339 date to the day, keeping
+// anything it can't parse as is
+func formatStatsDate(date string) string {
+	if date == "" {
+		return "none"
+	}
+	parsed, err := time.Parse(time.RFC3339, date)
+	if err != nil {
+		return date
+	}
+	return parsed.Format(time.DateOnly)
+}
+
+// formatBytes renders a size in bytes with a binary unit, like 1.5 MiB
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
+)
+
+// syncResult counts what syncing the feed list from OPML changed
+type syncResult struct {
+	added   int
+	removed int
+	failed  int
+}
+
+// syncFeeds implements `feeder sync [-prune-missing] [path...]`, making the
+// feed list follow OPML files. Without paths it syncs from opml_dir.
+func syncFeeds(
+	ctx context.Context,
+	db *sql.DB,
+	queries *database.Queries,
+	opts fetch.Options,
+	opmlDir string,
+	defaultPrune bool,
+	args []string,
+) error {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	pruneMissing := fs.Bool("prune-missing", defaultPrune, "remove feeds, and their posts, that no file lists")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: feeder sync [-prune-missing] [file.opml or directory...]")
+		fmt.Fprint(fs.Output(), "\nWithout arguments the OPML files in opml_dir ($FEEDER_OPML_DIR) are used.\n\n")
+		fs.PrintDefaults()
+	}
+	paths := parseInterspersed(fs, args)
+	if len(paths) == 0 {
+		if opmlDir == "" {
+			fs.Usage()
+			return errors.New("sync expects OPML files or opml_dir to be set")
+		}
+		paths = []string{opmlDir}
+	}
+
+	result, err := syncOPML(ctx, db, queries, opts, paths, *pruneMissing)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Added %d feeds, removed %d, failed %d\n", result.added, result.removed, result.failed)
+	return nil
+}
+
+// autoSync syncs from opml_dir before a fetch, logging the result instead
+// of printing it
+func autoSync(
+	ctx context.Context,
+	db *sql.DB,
+	queries *database.Queries,
+	opts fetch.Options,
+	opmlDir string,
+	pruneMissing bool,
+) error {
+	result, err := syncOPML(ctx, db, queries, opts, []string{opmlDir}, pruneMissing)
+	if err != nil {
+		return err
+	}
+	if result != (syncResult{}) {
+		slog.Info("Synced feeds from OPML",
+			"added", result.added,
+			"removed", result.removed,
+			"failed", result.failed,
+		)
+	}
+	return nil
+}
+
+// syncOPML subscribes to the feeds listed in the OPML files at paths that
+// aren't subscribed to yet and, with pruneMissing, removes the feeds none
+// of them list. Directories are searched for .opml and .xml files.
+func syncOPML(
+	ctx context.Context,
+	db *sql.DB,
+	queries *database.Queries,
+	opts fetch.Options,
+	paths []string,
+	pruneMissing bool,
+) (syncResult, error) {
+	var result syncResult
+
+	outlines, err := readOPMLPaths(paths)
+	if err != nil {
+		return result, err
+	}
+	// An empty or misplaced directory shouldn't wipe every subscription
+	if len(outlines) == 0 && pruneMissing {
+		return result, fmt.Errorf("no feeds listed in %s, not removing every feed", strings.Join(paths, ", "))
+	}
+
+	feeds, err := queries.ListFeeds(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to list feeds: %w", err)
+	}
+	subscribed := make(map[string]bool, len(feeds))
+	for _, feed := range feeds {
+		subscribed[feed.Url] = true
+	}
+
+	listed := make(map[string]bool, len(outlines))
+	for _, outline := range outlines {
+		if listed[outline.XMLURL] {
+			continue
+		}
+		listed[outline.XMLURL] = true
+		if subscribed[outline.XMLURL] {
+			continue
+		}
+
+		detected := detectOutline(ctx, opts, outline)
+		// The files are the source of truth, so a URL that turns out to be
+		// a page linking to the feed is reported rather than replaced
+		if detected.URL != outline.XMLURL {
+			slog.Warn("OPML lists a page instead of its feed, skipping it",
+				"url", outline.XMLURL,
+				"feed", detected.URL,
+			)
+			result.failed++
+			continue
+		}
+		if err := queries.CreateFeed(ctx, outlineFeedParams(outline, detected)); err != nil {
+			slog.Warn("Failed adding feed", "url", outline.XMLURL, "err", err)
+			result.failed++
+			continue
+		}
+		slog.Info("Added feed from OPML", "feed", outline.XMLURL)
+		result.added++
+	}
+
+	if !pruneMissing {
+		return result, nil
+	}
+	for _, feed := range feeds {
+		if listed[feed.Url] {
+			continue
+		}
+		posts, err := queries.DeleteFeedCascade(ctx, feed.ID)
+		if err != nil {
+			return result, fmt.Errorf("failed to remove feed %q: %w", feed.Name, err)
+		}
+		slog.Info("Removed feed missing from OPML", "feed", feed.Name, "posts", posts)
+		result.removed++
+	}
+	return result, nil
+}
+
+// readOPMLPaths reads the feed outlines of every OPML file at paths
+func readOPMLPaths(paths []string) ([]Outline, error) {
+	var outlines []Outline
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		files := []string{path}
+		if info.IsDir() {
+			if files, err = opmlFiles(path); err != nil {
+				return nil, err
+			}
+		}
+		for _, file := range files {
+			found, err := readOPML(file)
+			if err != nil {
+				return nil, err
+			}
+			outlines = append(outlines, found...)
+		}
+	}
+	return outlines, nil
+}
+
+// opmlFiles lists the .opml and .xml files directly in dir
+func opmlFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".opml", ".xml":
+			if !entry.IsDir() {
+				files = append(files, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return files, nil
+}
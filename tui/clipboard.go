@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var errNoClipboard = errors.New("no clipboard available")
+
+type copyURLMsg struct {
+	url string
+	err error
+}
+
+func copyURLCmd(ctx context.Context, url string) tea.Cmd {
+	return func() tea.Msg {
+		return copyURLMsg{url: url, err: copyToClipboard(ctx, url)}
+	}
+}
+
+// copyToClipboard puts text on the system clipboard using whichever tool
+// the platform provides
+func copyToClipboard(ctx context.Context, text string) error {
+	var cmd string
+	var args []string
+
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = "pbcopy"
+	case "windows":
+		cmd = "clip"
+	default:
+		// Over SSH or on a console there is no display to own a clipboard
+		switch {
+		case os.Getenv("WAYLAND_DISPLAY") != "":
+			cmd = "wl-copy"
+		case os.Getenv("DISPLAY") == "":
+			return errNoClipboard
+		case hasCommand("xclip"):
+			cmd = "xclip"
+			args = []string{"-selection", "clipboard"}
+		default:
+			cmd = "xsel"
+			args = []string{"--clipboard", "--input"}
+		}
+	}
+
+	if !hasCommand(cmd) {
+		return errNoClipboard
+	}
+
+	c := exec.CommandContext(ctx, cmd, args...)
+	c.Stdin = strings.NewReader(text)
+	return c.Run()
+}
+
+func hasCommand(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
@@ -0,0 +1,34 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dbTimeout bounds every database operation of the TUI. SQLite waits up to
+// its busy_timeout for a lock, so this mostly catches queries that are
+// slow rather than blocked.
+const dbTimeout = 10 * time.Second
+
+// dbContext derives the context for one database operation, so a contended
+// database shows up as an error instead of a command that never returns
+func dbContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, dbTimeout)
+}
+
+// dbErrorStatus shows a failed database operation in the status bar
+func (m model) dbErrorStatus(err error) (model, tea.Cmd) {
+	text := "Database error: " + err.Error()
+	switch {
+	case database.IsBusy(err):
+		text = "The database is busy, a fetch may be running; try again"
+	case errors.Is(err, context.DeadlineExceeded):
+		text = "The database took too long to respond, try again"
+	}
+	cmd := m.list.NewStatusMessage(feedErrorStyle.Render(text))
+	return m, cmd
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aaronzipp/feeder/database"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type deletePostMsg struct {
+	postID int64
+	err    error
+}
+
+func deletePostCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		err := queries.DeletePostForGood(ctx, postID)
+		return deletePostMsg{postID: postID, err: err}
+	}
+}
+
+// deletePrompt asks for the pending deletion in the title bar, a second
+// time for starred posts
+func (m model) deletePrompt() string {
+	title := ansi.Truncate(m.deletingPost.Title, 40, "…")
+	if m.starredConfirmed {
+		return fmt.Sprintf("%q is starred, delete it anyway? (y/n)", title)
+	}
+	return fmt.Sprintf("Delete %q for good? (y/n)", title)
+}
+
+// updateConfirmPostDelete resolves the pending post deletion: "y" deletes,
+// or for a starred post asks once more, and any other key cancels
+func (m model) updateConfirmPostDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	post := *m.deletingPost
+	if msg.String() != "y" {
+		m.deletingPost = nil
+		m.starredConfirmed = false
+		return m, nil
+	}
+	if post.IsStarred.Valid && post.IsStarred.Int64 == 1 && !m.starredConfirmed {
+		m.starredConfirmed = true
+		return m, nil
+	}
+
+	m.deletingPost = nil
+	m.starredConfirmed = false
+	// There's nothing left to undo once the post is gone
+	if m.lastAction != nil && m.lastAction.post.ID == post.ID {
+		m.lastAction = nil
+	}
+	return m.removePost(post.ID), deletePostCmd(m.ctx, m.queries, post.ID)
+}
@@ -0,0 +1,212 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// postContentMsg carries the full text of a post opened in the reading
+// pane, which isn't loaded with the list
+type postContentMsg struct {
+	postID  int64
+	content string
+	err     error
+}
+
+func postContentCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		content, err := queries.GetPostContent(ctx, postID)
+		return postContentMsg{postID: postID, content: content.String, err: err}
+	}
+}
+
+// openDetail shows post in the reading pane, starting with its summary
+// until the full text is loaded
+func (m model) openDetail(post database.PostWithFeed) (model, tea.Cmd) {
+	m.view = viewDetail
+	m.detailPost = post
+	m.detailContent = ""
+	m.detailOffset = 0
+	m = m.wrapDetailBody()
+	return m, postContentCmd(m.ctx, m.queries, post.ID)
+}
+
+// updateDetail handles keys while the reading pane is open
+func (m model) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	_, body, _ := m.detailLayout()
+	page := max(1, m.detailBodyHeight()/2)
+	maxOffset := max(0, len(body)-m.detailBodyHeight())
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "q", "v", " ":
+		m.view = viewList
+		m.openErr = ""
+
+	case "j", "down":
+		m.detailOffset = min(m.detailOffset+1, maxOffset)
+	case "k", "up":
+		m.detailOffset = max(m.detailOffset-1, 0)
+	case "ctrl+d", "pgdown":
+		m.detailOffset = min(m.detailOffset+page, maxOffset)
+	case "ctrl+u", "pgup":
+		m.detailOffset = max(m.detailOffset-page, 0)
+	case "g", "home":
+		m.detailOffset = 0
+	case "G", "end":
+		m.detailOffset = maxOffset
+
+	case "enter":
+		return m, m.openBrowserCmd(m.detailPost, openURL(m.detailPost))
+
+	case "p":
+		if m.detailPost.EnclosureUrl.Valid {
+			return m, m.openBrowserCmd(m.detailPost, m.detailPost.EnclosureUrl.String)
+		}
+
+	case "b":
+		return m, m.openBrowserCmd(m.detailPost, m.detailPost.Url)
+	}
+
+	return m, nil
+}
+
+// wrappedBody is a post's text split into lines wrapped to width
+type wrappedBody struct {
+	postID int64
+	width  int
+	lines  []string
+}
+
+// detailWidth is how wide the reading pane's text is, inside its padding
+func (m model) detailWidth() int {
+	if width := m.width - 4; width > 0 {
+		return width
+	}
+	return 80
+}
+
+// wrapDetailBody wraps the text of the post in the reading pane again,
+// after the post, its loaded text or the width changed
+func (m model) wrapDetailBody() model {
+	// The full text once it's loaded, the summary until then or when the
+	// feed only gave a summary
+	text := m.detailContent
+	if strings.TrimSpace(text) == "" {
+		text = m.detailPost.Summary.String
+	}
+	if strings.TrimSpace(text) == "" {
+		text = "No summary available."
+	}
+	width := m.detailWidth()
+	m.detailBody = wrappedBody{
+		postID: m.detailPost.ID,
+		width:  width,
+		lines:  strings.Split(lipgloss.NewStyle().Width(width).Render(text), "\n"),
+	}
+	return m
+}
+
+// detailLayout renders the reading pane's parts: the post's metadata, its
+// text split into lines, wrapped to the terminal width, and the key hints
+func (m model) detailLayout() (string, []string, string) {
+	post := m.detailPost
+	wrap := lipgloss.NewStyle().Width(m.detailWidth())
+
+	published := post.PublishedAt
+	if t, err := time.Parse(time.RFC3339, post.PublishedAt); err == nil {
+		published = t.Local().Format("Monday, January 2, 2006 15:04")
+	}
+	if (postItem{post: post}).dateEstimated() {
+		published = "Unknown date"
+	}
+
+	body := m.detailBody
+	if body.postID != post.ID || body.width != m.detailWidth() {
+		body = m.wrapDetailBody().detailBody
+	}
+
+	byline := feedNameStyle.Render(post.FeedName)
+	if post.Author.Valid {
+		byline += dateStyle.Render(" by ") + titleStyle.Render(post.Author.String)
+	}
+
+	sections := []string{
+		detailTitleStyle.Render(wrap.Render(post.Title)),
+		byline + "  " + dateStyle.Render(published),
+	}
+	if post.Categories.Valid {
+		tags := strings.Split(post.Categories.String, ",")
+		sections = append(sections, dateStyle.Render(wrap.Render("#"+strings.Join(tags, " #"))))
+	}
+	if enclosure := enclosureLine(post); enclosure != "" {
+		sections = append(sections, feedNameStyle.Render(wrap.Render(enclosure)))
+	}
+	if post.FeedError.Valid {
+		sections = append(sections, feedErrorStyle.Render(wrap.Render("⚠ Last fetch failed: "+post.FeedError.String)))
+	}
+	sections = append(sections, "")
+
+	hint := "enter open in browser • esc back"
+	switch {
+	case hasAudio(post):
+		hint = "enter play episode • b open post • esc back"
+	case post.EnclosureUrl.Valid:
+		hint = "enter open in browser • p download attachment • esc back"
+	}
+	footer := []string{detailHintStyle.Render(hint)}
+	if m.openErr != "" {
+		footer = append(footer, feedErrorStyle.Render(wrap.Render(m.openErr)))
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...),
+		body.lines,
+		lipgloss.JoinVertical(lipgloss.Left, footer...)
+}
+
+// detailBodyHeight is how many lines of the post's text fit between the
+// metadata and the hints, or every line while the height is unknown
+func (m model) detailBodyHeight() int {
+	header, body, footer := m.detailLayout()
+	if m.height == 0 {
+		return len(body)
+	}
+	// The pane is padded by a line above and below
+	height := max(1, m.height-2-lipgloss.Height(header)-lipgloss.Height(footer))
+	// Text that doesn't fit gives up a line to show the scroll position
+	if len(body) > height {
+		height = max(1, height-1)
+	}
+	return height
+}
+
+// detailView renders the selected post's metadata and as much of its text
+// as fits, scrolled to detailOffset
+func (m model) detailView() string {
+	header, body, footer := m.detailLayout()
+	height := m.detailBodyHeight()
+
+	offset := min(m.detailOffset, max(0, len(body)-height))
+	visible := body[offset:min(len(body), offset+height)]
+	if len(body) > height {
+		position := fmt.Sprintf(" %d%%", 100*(offset+len(visible))/len(body))
+		footer = dateStyle.Render("j/k scroll"+position) + "\n" + footer
+	}
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(lipgloss.JoinVertical(lipgloss.Left,
+		header,
+		strings.Join(visible, "\n"),
+		footer,
+	))
+}
@@ -0,0 +1,103 @@
+package tui
+
+import (
+	"fmt"
+	"mime"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+// enclosureType returns the MIME type of the post's enclosure, guessing
+// from the file extension when the feed didn't say
+func enclosureType(post database.PostWithFeed) string {
+	if post.EnclosureType.Valid {
+		return post.EnclosureType.String
+	}
+	u, err := url.Parse(post.EnclosureUrl.String)
+	if err != nil {
+		return ""
+	}
+	mediaType, _, _ := strings.Cut(mime.TypeByExtension(path.Ext(u.Path)), ";")
+	return mediaType
+}
+
+// hasAudio reports whether the post is an episode with an audio enclosure
+func hasAudio(post database.PostWithFeed) bool {
+	return post.EnclosureUrl.Valid && strings.HasPrefix(enclosureType(post), "audio/")
+}
+
+// openURL is what enter opens: the episode for audio posts, otherwise the
+// post itself
+func openURL(post database.PostWithFeed) string {
+	if hasAudio(post) {
+		return post.EnclosureUrl.String
+	}
+	return post.Url
+}
+
+// enclosureLine describes the post's enclosure for the reading pane, or
+// returns "" when it has none
+func enclosureLine(post database.PostWithFeed) string {
+	if !post.EnclosureUrl.Valid {
+		return ""
+	}
+
+	icon, parts := "📎", []string{}
+	if hasAudio(post) {
+		icon = "🎧"
+	}
+	if mediaType := enclosureType(post); mediaType != "" {
+		parts = append(parts, mediaType)
+	}
+	if post.EnclosureDuration.Valid {
+		parts = append(parts, formatDuration(post.EnclosureDuration.Int64))
+	}
+	if post.EnclosureLength.Valid {
+		parts = append(parts, formatSize(post.EnclosureLength.Int64))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "attachment")
+	}
+	return icon + " " + strings.Join(parts, " · ")
+}
+
+// durationLabel is the running time shown in the list for episodes, or ""
+// for posts without audio or a known duration
+func durationLabel(post database.PostWithFeed) string {
+	if !post.EnclosureDuration.Valid || !hasAudio(post) {
+		return ""
+	}
+	return formatDuration(post.EnclosureDuration.Int64)
+}
+
+// formatDuration renders a running time in seconds as e.g. "1h05m" or
+// "42m", leaving out seconds unless it's shorter than a minute
+func formatDuration(seconds int64) string {
+	hours, minutes := seconds/3600, seconds%3600/60
+	switch {
+	case hours > 0:
+		return fmt.Sprintf("%dh%02dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm", minutes)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// formatSize renders a byte count with a decimal unit, as download sizes
+// are usually given
+func formatSize(bytes int64) string {
+	const unit = 1000
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	size, exp := float64(bytes)/unit, 0
+	for size >= unit && exp < 3 {
+		size /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", size, "kMGT"[exp])
+}
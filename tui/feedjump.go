@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// feedRunStart returns the index in items of the first post of the next
+// run of posts from one feed after the cursor, or with back set of the
+// run before the one the cursor is in. It returns -1 when there is none.
+func feedRunStart(items []list.Item, cursor int, back bool) int {
+	feedAt := func(i int) (int64, bool) {
+		if item, ok := items[i].(postItem); ok {
+			return item.post.FeedID, true
+		}
+		return 0, false
+	}
+	current, ok := feedAt(cursor)
+	if !ok {
+		return -1
+	}
+
+	if !back {
+		for i := cursor + 1; i < len(items); i++ {
+			if feed, ok := feedAt(i); ok && feed != current {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// Find the last post of the previous run, then walk to where it starts
+	i := cursor - 1
+	for ; i >= 0; i-- {
+		if feed, ok := feedAt(i); ok && feed != current {
+			break
+		}
+	}
+	if i < 0 {
+		return -1
+	}
+	previous, _ := feedAt(i)
+	for i > 0 {
+		if feed, ok := feedAt(i - 1); !ok || feed != previous {
+			break
+		}
+		i--
+	}
+	return i
+}
+
+// jumpFeed moves the cursor to the next or previous feed in the list, so
+// a date-sorted inbox can be triaged one feed at a time
+func (m model) jumpFeed(back bool) (model, tea.Cmd) {
+	index := feedRunStart(m.list.VisibleItems(), m.list.Index(), back)
+	if index < 0 {
+		return m, nil
+	}
+	m.list.Select(index)
+	return m.maybeLoadMore()
+}
@@ -0,0 +1,263 @@
+package tui
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// feedItem implements list.Item for the Feeds screen
+type feedItem struct {
+	feed database.Feed
+}
+
+func (i feedItem) FilterValue() string {
+	return i.feed.Name + " " + i.feed.Url
+}
+
+func (i feedItem) isEnabled() bool {
+	return !i.feed.IsEnabled.Valid || i.feed.IsEnabled.Int64 != 0
+}
+
+func (i feedItem) lastUpdated() string {
+	if !i.feed.LastUpdatedAt.Valid || i.feed.LastUpdatedAt.String == "" {
+		return "never"
+	}
+	return formatDate(i.feed.LastUpdatedAt.String)
+}
+
+// refreshIntervals are the choices "i" cycles through on the Feeds screen
+var refreshIntervals = []time.Duration{0, time.Hour, 6 * time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+func (i feedItem) interval() string {
+	label := formatInterval(fetch.RefreshInterval(i.feed))
+	if !i.feed.RefreshIntervalSeconds.Valid && i.feed.SuggestedIntervalSeconds.Valid {
+		label += " (feed)"
+	}
+	return label
+}
+
+func formatInterval(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "every run"
+	case d%(7*24*time.Hour) == 0:
+		return fmt.Sprintf("every %dw", d/(7*24*time.Hour))
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("every %dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("every %dh", d/time.Hour)
+	default:
+		return "every " + d.String()
+	}
+}
+
+// nextInterval returns the refresh interval after the one set for the feed,
+// starting over from the first for intervals set outside the TUI. Zero goes
+// back to the interval the feed asks for.
+func (i feedItem) nextInterval() time.Duration {
+	userInterval := time.Duration(i.feed.RefreshIntervalSeconds.Int64) * time.Second
+	index := slices.Index(refreshIntervals, userInterval)
+	return refreshIntervals[(index+1)%len(refreshIntervals)]
+}
+
+// feedDelegate renders feeds in the same tabular style as posts
+type feedDelegate struct {
+	nameWidth     int
+	typeWidth     int
+	intervalWidth int
+	dateWidth     int
+}
+
+func newFeedDelegate(items []list.Item) feedDelegate {
+	d := feedDelegate{}
+	for _, item := range items {
+		if fi, ok := item.(feedItem); ok {
+			d.nameWidth = max(d.nameWidth, lipgloss.Width(fi.feed.Name))
+			d.typeWidth = max(d.typeWidth, lipgloss.Width(fi.feed.FeedType))
+			d.intervalWidth = max(d.intervalWidth, lipgloss.Width(fi.interval()))
+			d.dateWidth = max(d.dateWidth, lipgloss.Width(fi.lastUpdated()))
+		}
+	}
+	return d
+}
+
+func (d feedDelegate) Height() int {
+	return 1
+}
+
+func (d feedDelegate) Spacing() int {
+	return 1
+}
+
+func (d feedDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	return nil
+}
+
+func (d feedDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	i, ok := item.(feedItem)
+	if !ok {
+		return
+	}
+
+	cursor := "  "
+	if index == m.Index() {
+		cursor = cursorStyle.Render("❯ ")
+	}
+
+	var styledName string
+	if index == m.Index() {
+		styledName = selectedStyle.Render(padRight(i.feed.Name, d.nameWidth))
+	} else {
+		styledName = titleStyle.Render(padRight(i.feed.Name, d.nameWidth))
+	}
+	styledType := feedNameStyle.Render(padRight(i.feed.FeedType, d.typeWidth))
+	styledInterval := dateStyle.Render(padRight(i.interval(), d.intervalWidth))
+	styledDate := dateStyle.Render(padRight(i.lastUpdated(), d.dateWidth))
+
+	// The status column gets whatever width is left
+	statusWidth := max(10, m.Width()-2-d.nameWidth-d.typeWidth-d.intervalWidth-d.dateWidth-8)
+	styledStatus := dateStyle.Render("ok")
+	switch {
+	case !i.isEnabled():
+		styledStatus = dateStyle.Render("disabled")
+	case i.feed.LastError.Valid:
+		styledStatus = feedErrorStyle.Render(ansi.Truncate("⚠ "+i.feed.LastError.String, statusWidth, "..."))
+	}
+
+	fmt.Fprint(w, cursor+styledName+"  "+styledType+"  "+styledInterval+"  "+styledDate+"  "+styledStatus)
+}
+
+type setFeedEnabledMsg struct {
+	feedID int64
+	err    error
+}
+
+type setFeedIntervalMsg struct {
+	feedID int64
+	err    error
+}
+
+type archiveFeedMsg struct {
+	feed     string
+	archived int64
+	err      error
+}
+
+type loadFeedsMsg struct {
+	feeds []database.Feed
+	err   error
+}
+
+type deleteFeedMsg struct {
+	feedID int64
+	err    error
+}
+
+func loadFeedsCmd(ctx context.Context, queries *database.Queries) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		feeds, err := queries.ListFeeds(ctx)
+		return loadFeedsMsg{feeds: feeds, err: err}
+	}
+}
+
+func deleteFeedCmd(ctx context.Context, queries *database.Queries, feedID int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		_, err := queries.DeleteFeedCascade(ctx, feedID)
+		return deleteFeedMsg{feedID: feedID, err: err}
+	}
+}
+
+// archiveFeedCmd archives the feed's posts in the inbox, leaving starred and
+// snoozed ones alone like archiving the whole inbox does
+func archiveFeedCmd(ctx context.Context, queries *database.Queries, feed database.Feed) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		archived, err := queries.ArchiveInbox(ctx, database.ListOptions{FeedID: feed.ID})
+		return archiveFeedMsg{feed: feed.Name, archived: archived, err: err}
+	}
+}
+
+func setFeedEnabledCmd(ctx context.Context, queries *database.Queries, feedID int64, enabled bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		var isEnabled int64
+		if enabled {
+			isEnabled = 1
+		}
+		err := queries.SetFeedEnabled(ctx, database.SetFeedEnabledParams{
+			IsEnabled: sql.NullInt64{Int64: isEnabled, Valid: true},
+			ID:        feedID,
+		})
+		return setFeedEnabledMsg{feedID: feedID, err: err}
+	}
+}
+
+func setFeedIntervalCmd(ctx context.Context, queries *database.Queries, feedID int64, interval time.Duration) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		err := queries.SetFeedRefreshInterval(ctx, database.SetFeedRefreshIntervalParams{
+			RefreshIntervalSeconds: sql.NullInt64{Int64: int64(interval / time.Second), Valid: interval > 0},
+			ID:                     feedID,
+		})
+		return setFeedIntervalMsg{feedID: feedID, err: err}
+	}
+}
+
+// updateConfirmDelete resolves the pending feed deletion: "y" deletes, any
+// other key cancels
+func (m model) updateConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	feed := *m.pendingDelete
+	m.pendingDelete = nil
+
+	if msg.String() == "y" {
+		return m, deleteFeedCmd(m.ctx, m.queries, feed.ID)
+	}
+	return m, nil
+}
+
+// updateFeedsKey handles keys that only apply on the Feeds screen. It
+// reports whether the key was consumed.
+func (m model) updateFeedsKey(key string) (model, tea.Cmd, bool) {
+	item, ok := m.list.SelectedItem().(feedItem)
+	if !ok {
+		return m, nil, false
+	}
+
+	switch key {
+	case "d":
+		feed := item.feed
+		m.pendingDelete = &feed
+		return m, nil, true
+	case "e":
+		return m, setFeedEnabledCmd(m.ctx, m.queries, item.feed.ID, !item.isEnabled()), true
+	case "i":
+		return m, setFeedIntervalCmd(m.ctx, m.queries, item.feed.ID, item.nextInterval()), true
+	case "x":
+		return m, archiveFeedCmd(m.ctx, m.queries, item.feed), true
+	}
+
+	return m, nil, false
+}
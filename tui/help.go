@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpBinding is one line of the help overlay
+type helpBinding struct {
+	keys string
+	desc string
+}
+
+type helpGroup struct {
+	title    string
+	bindings []helpBinding
+}
+
+// helpGroups documents every key; keep it in step with Update
+var helpGroups = []helpGroup{
+	{"Screens", []helpBinding{
+		{"1", "inbox"},
+		{"2", "starred"},
+		{"3", "archive"},
+		{"4", "feeds"},
+		{"5", "new since you last caught up"},
+		{"S", "search all posts"},
+		{"J", "go to the result's screen (Search)"},
+		{"esc", "leave search or clear filters"},
+	}},
+	{"Navigation", []helpBinding{
+		{"j/k ↑/↓", "move down/up"},
+		{"h/l ←/→", "previous/next page"},
+		{"gg / G", "first/last item"},
+		{"] / [", "next/previous feed in the list"},
+		{"/", "filter the list by text"},
+		{"v / space", "read the post's summary"},
+		{"enter", "open the post, or play its episode"},
+		{"O", "open every post in the list"},
+	}},
+	{"Posts", []helpBinding{
+		{"x", "archive"},
+		{"X", "open, archive and go to the next"},
+		{"R", "reading mode: enter opens, archives"},
+		{"A", "archive the whole inbox"},
+		{"d", "delete for good, asks to confirm"},
+		{"u", "unarchive, or unstar on Starred"},
+		{"s", "star"},
+		{"z", "snooze until later"},
+		{"m", "toggle read"},
+		{"y", "copy the URL"},
+		{"U / ctrl+z", "undo the last archive or star change"},
+		{"c", "mark everything as seen (New screen)"},
+	}},
+	{"Lists", []helpBinding{
+		{"f", "show posts from one feed"},
+		{"t", "show posts with one tag"},
+		{"o", "change the sort order"},
+		{"r", "fetch all feeds"},
+	}},
+	{"Reading pane", []helpBinding{
+		{"j/k ↑/↓", "scroll the post's text"},
+		{"p", "play or download attached media"},
+		{"b", "open the post, not its episode"},
+	}},
+	{"Feeds screen", []helpBinding{
+		{"d", "delete the feed and its posts"},
+		{"e", "enable or disable the feed"},
+		{"i", "change how often the feed is fetched"},
+		{"x", "archive the feed's inbox posts"},
+	}},
+	{"General", []helpBinding{
+		{"?", "toggle this help"},
+		{"q / ctrl+c", "quit"},
+	}},
+}
+
+// updateHelp handles keys while the help overlay is open
+func (m model) updateHelp(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "?", "esc", "q":
+		m.view = viewList
+	}
+	return m, nil
+}
+
+// helpView renders the help overlay, laying the groups out in two columns
+// when the terminal is wide enough
+func (m model) helpView() string {
+	keyWidth := 0
+	for _, group := range helpGroups {
+		for _, binding := range group.bindings {
+			keyWidth = max(keyWidth, lipgloss.Width(binding.keys))
+		}
+	}
+
+	blocks := make([]string, len(helpGroups))
+	for i, group := range helpGroups {
+		lines := []string{feedNameStyle.Render(group.title)}
+		for _, binding := range group.bindings {
+			lines = append(lines, helpKeyStyle.Render(padRight(binding.keys, keyWidth))+"  "+titleStyle.Render(binding.desc))
+		}
+		blocks[i] = strings.Join(lines, "\n") + "\n"
+	}
+
+	columnWidth := 0
+	for _, block := range blocks {
+		columnWidth = max(columnWidth, lipgloss.Width(block))
+	}
+
+	var body string
+	if m.width >= 2*columnWidth+8 {
+		// Split where the left column reaches half the lines
+		total := strings.Count(strings.Join(blocks, ""), "\n")
+		lines, half := 0, 0
+		for half < len(blocks) && 2*lines < total {
+			lines += strings.Count(blocks[half], "\n")
+			half++
+		}
+		left := lipgloss.JoinVertical(lipgloss.Left, blocks[:half]...)
+		right := lipgloss.JoinVertical(lipgloss.Left, blocks[half:]...)
+		body = lipgloss.JoinHorizontal(lipgloss.Top, lipgloss.NewStyle().Width(columnWidth+4).Render(left), right)
+	} else {
+		body = lipgloss.JoinVertical(lipgloss.Left, blocks...)
+	}
+
+	return lipgloss.NewStyle().Padding(1, 2).Render(
+		lipgloss.JoinVertical(lipgloss.Left,
+			detailTitleStyle.Render("Keys"),
+			body,
+			dateStyle.Render("? or esc to close"),
+		),
+	)
+}
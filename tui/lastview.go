@@ -0,0 +1,52 @@
+package tui
+
+import (
+	"github.com/aaronzipp/feeder/database"
+)
+
+// screenNamed returns the screen whose String is name. Search isn't
+// reopened, its query isn't kept.
+func screenNamed(name string) (screenType, bool) {
+	for _, screen := range []screenType{screenInbox, screenArchive, screenStarred, screenFeeds, screenNew} {
+		if screen.String() == name {
+			return screen, true
+		}
+	}
+	return screenInbox, false
+}
+
+// lastView returns the screen and selected post to reopen on next launch
+func (m model) lastView() (string, int64) {
+	if m.currentScreen == screenSearch {
+		return screenInbox.String(), 0
+	}
+	var postID int64
+	if item, ok := m.list.SelectedItem().(postItem); ok {
+		postID = item.post.ID
+	}
+	return m.currentScreen.String(), postID
+}
+
+// restoreView reopens the screen the TUI was closed on and selects the post
+// once it is listed. Screens other than the inbox are loaded by Init.
+func (m model) restoreView(screenName string, postID int64) model {
+	screen, ok := screenNamed(screenName)
+	if !ok {
+		return m
+	}
+	// When the post is gone the cursor stays at the top
+	m.pendingSelect = postID
+	if screen != screenInbox {
+		return m.switchScreen(screen)
+	}
+	return m.selectPendingPost()
+}
+
+// saveLastView remembers where the user was for the next launch
+func saveLastView(m model, queries *database.Queries) error {
+	ctx, cancel := dbContext(m.ctx)
+	defer cancel()
+
+	screen, postID := m.lastView()
+	return queries.SetLastView(ctx, screen, postID)
+}
@@ -0,0 +1,153 @@
+package tui
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// belongsOn reports whether a post in its current state is listed on
+// screen. Filters and snoozes aren't checked, acting on a post doesn't
+// change them.
+func belongsOn(screen screenType, post database.PostWithFeed) bool {
+	archived := post.IsArchived.Valid && post.IsArchived.Int64 == 1
+	starred := post.IsStarred.Valid && post.IsStarred.Int64 == 1
+	switch screen {
+	case screenInbox:
+		return !archived && !starred
+	case screenNew:
+		return !archived
+	case screenArchive:
+		return archived
+	case screenStarred:
+		return starred
+	default:
+		return true
+	}
+}
+
+// postIndex returns where the post is among the loaded items, or -1
+func (m model) postIndex(postID int64) int {
+	return slices.IndexFunc(m.list.Items(), func(item list.Item) bool {
+		post, ok := item.(postItem)
+		return ok && post.post.ID == postID
+	})
+}
+
+// setPost shows a change to a post in the list right away, while it's
+// still being written, instead of loading the whole screen again: the
+// post's item is updated, or removed when the post no longer belongs on
+// the screen. A post that isn't listed but belongs again, as after an undo,
+// is put back at index.
+func (m model) setPost(post database.PostWithFeed, index int) (model, tea.Cmd) {
+	i := m.postIndex(post.ID)
+	belongs := belongsOn(m.currentScreen, post)
+
+	var cmd tea.Cmd
+	switch {
+	case i >= 0 && belongs:
+		cmd = m.list.SetItem(i, postItem{post: post})
+	case i >= 0:
+		return m.removePost(post.ID), nil
+	case belongs:
+		cmd = m.list.InsertItem(min(max(index, 0), len(m.list.Items())), postItem{post: post})
+	default:
+		return m, nil
+	}
+	// The columns are sized to the items, and a new mark may need room
+	m.list.SetDelegate(newDelegate(m.list.Items(), m.currentScreen, m.feedWidth))
+	return m, cmd
+}
+
+// changePost applies change to post and shows it in the list, batched with
+// write, the command storing the change
+func (m model) changePost(post database.PostWithFeed, change func(*database.PostWithFeed), write tea.Cmd) (model, tea.Cmd) {
+	change(&post)
+	m, cmd := m.setPost(post, 0)
+	return m, tea.Batch(cmd, write)
+}
+
+// listedPost returns the loaded post with the ID, which may have left the
+// list since a command started
+func (m model) listedPost(postID int64) (database.PostWithFeed, bool) {
+	if i := m.postIndex(postID); i >= 0 {
+		return m.list.Items()[i].(postItem).post, true
+	}
+	return database.PostWithFeed{}, false
+}
+
+func setFlag(flag *sql.NullInt64, on bool) {
+	*flag = sql.NullInt64{Valid: true}
+	if on {
+		flag.Int64 = 1
+	}
+}
+
+func asArchived(post *database.PostWithFeed)   { setFlag(&post.IsArchived, true) }
+func asUnarchived(post *database.PostWithFeed) { setFlag(&post.IsArchived, false) }
+func asStarred(post *database.PostWithFeed)    { setFlag(&post.IsStarred, true) }
+func asRead(post *database.PostWithFeed)       { setFlag(&post.IsRead, true) }
+func asUnread(post *database.PostWithFeed)     { setFlag(&post.IsRead, false) }
+
+// asUnstarred is how the Starred screen's unstar leaves a post: archived too,
+// since it's done with
+func asUnstarred(post *database.PostWithFeed) {
+	setFlag(&post.IsStarred, false)
+	setFlag(&post.IsArchived, true)
+}
+
+// removePost takes a post out of the list, keeping the cursor on the post
+// that moved up into its place or on the pending post
+func (m model) removePost(postID int64) model {
+	i := m.postIndex(postID)
+	if i < 0 {
+		return m
+	}
+	m.list.RemoveItem(i)
+	if visible := len(m.list.VisibleItems()); visible > 0 && m.list.Index() >= visible {
+		m.list.Select(visible - 1)
+	}
+	return m.selectPendingPost()
+}
+
+// postWrittenCmd follows a post change that was already shown in the list
+// once it's stored, refreshing only the counts that depend on it
+func (m model) postWrittenCmd() tea.Cmd {
+	return tea.Batch(screenCountsCmd(m.ctx, m.queries), unreadCountCmd(m.ctx, m.queries))
+}
+
+// postWriteFailed reports a post change that couldn't be stored and loads
+// the screen again, undoing what the list already shows
+func (m model) postWriteFailed(err error) (model, tea.Cmd) {
+	m, status := m.dbErrorStatus(err)
+	return m, tea.Batch(status, m.reloadCmd())
+}
+
+type unreadCountMsg struct {
+	unread database.UnreadCountRow
+	err    error
+}
+
+func unreadCountCmd(ctx context.Context, queries *database.Queries) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		unread, err := queries.UnreadCount(ctx)
+		return unreadCountMsg{unread: unread, err: err}
+	}
+}
+
+// setUnreadStatus shows the unread summary after the post count, which the
+// status bar prints before the item name
+func (m model) setUnreadStatus(unread database.UnreadCountRow) model {
+	m.list.SetStatusBarItemName(
+		"post"+unreadStatus(unread),
+		"posts"+unreadStatus(unread),
+	)
+	return m
+}
@@ -0,0 +1,83 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// archivePageSize is how many archived posts are loaded at a time, since
+	// the archive only ever grows
+	archivePageSize = 200
+	// loadMoreThreshold is how close the cursor gets to the last loaded post
+	// before the next page is fetched
+	loadMoreThreshold = 20
+)
+
+type loadMoreMsg struct {
+	screen screenType
+	offset int
+	posts  []database.PostWithFeed
+	more   bool
+	err    error
+}
+
+func loadMoreCmd(ctx context.Context, queries *database.Queries, opts database.ListOptions) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		posts, err := queries.ListArchive(ctx, opts)
+		return loadMoreMsg{
+			screen: screenArchive,
+			offset: opts.Offset,
+			posts:  posts,
+			more:   len(posts) == opts.Limit,
+			err:    err,
+		}
+	}
+}
+
+// maybeLoadMore starts loading the next page of the archive when the cursor
+// nears the end of what's loaded
+func (m model) maybeLoadMore() (model, tea.Cmd) {
+	if m.currentScreen != screenArchive || !m.morePosts || m.loadingMore {
+		return m, nil
+	}
+	// The cursor moves through the matches while filtering
+	if m.list.FilterState() != list.Unfiltered {
+		return m, nil
+	}
+	loaded := len(m.list.Items())
+	if m.list.Index() < loaded-loadMoreThreshold {
+		return m, nil
+	}
+
+	opts := m.listOptions()
+	opts.Offset = loaded
+	opts.Limit = archivePageSize
+	m.loadingMore = true
+	return m, loadMoreCmd(m.ctx, m.queries, opts)
+}
+
+// appendPosts adds the next page of the archive below what's loaded
+func (m model) appendPosts(msg loadMoreMsg) model {
+	m.loadingMore = false
+	// A reload or a screen change since the request makes the page stale
+	if msg.err != nil || msg.screen != m.currentScreen || msg.offset != len(m.list.Items()) {
+		return m
+	}
+
+	items := m.list.Items()
+	for _, post := range msg.posts {
+		items = append(items, postItem{post: post})
+	}
+	m.list.SetItems(items)
+	m.list.SetDelegate(newDelegate(items, m.currentScreen, m.feedWidth))
+	m.archiveLimit = len(items)
+	m.morePosts = msg.more
+	return m
+}
@@ -0,0 +1,144 @@
+package tui
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// pickerKind says what the list is temporarily showing instead of posts
+// while the user chooses a filter
+type pickerKind int
+
+const (
+	pickerNone pickerKind = iota
+	pickerFeed
+	pickerTag
+)
+
+// tagItem implements list.Item for the tag picker
+type tagItem struct {
+	name  string
+	count int
+}
+
+func (i tagItem) FilterValue() string {
+	return i.name
+}
+
+// tagDelegate renders a tag followed by how many loaded posts carry it
+type tagDelegate struct{}
+
+func (d tagDelegate) Height() int {
+	return 1
+}
+
+func (d tagDelegate) Spacing() int {
+	return 1
+}
+
+func (d tagDelegate) Update(msg tea.Msg, m *list.Model) tea.Cmd {
+	return nil
+}
+
+func (d tagDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	i, ok := item.(tagItem)
+	if !ok {
+		return
+	}
+
+	cursor := "  "
+	styledName := titleStyle.Render(i.name)
+	if index == m.Index() {
+		cursor = cursorStyle.Render("❯ ")
+		styledName = selectedStyle.Render(i.name)
+	}
+
+	fmt.Fprint(w, cursor+styledName+"  "+dateStyle.Render(strconv.Itoa(i.count)))
+}
+
+// tagItems collects the categories of the given posts, most used first
+func tagItems(items []list.Item) []list.Item {
+	counts := make(map[string]int)
+	for _, item := range items {
+		post, ok := item.(postItem)
+		if !ok || !post.post.Categories.Valid {
+			continue
+		}
+		for tag := range strings.SplitSeq(post.post.Categories.String, ",") {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]tagItem, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, tagItem{name: name, count: count})
+	}
+	slices.SortFunc(tags, func(a, b tagItem) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), strings.Compare(a.name, b.name))
+	})
+
+	result := make([]list.Item, len(tags))
+	for i, tag := range tags {
+		result[i] = tag
+	}
+	return result
+}
+
+// openTagPicker swaps the loaded posts for their tags. Nothing happens when
+// none of the posts are tagged.
+func (m model) openTagPicker() model {
+	items := tagItems(m.list.Items())
+	if len(items) == 0 {
+		return m
+	}
+
+	m.picker = pickerTag
+	m.list.ResetFilter()
+	m.list.SetItems(items)
+	m.list.SetDelegate(tagDelegate{})
+	m.list.SetStatusBarItemName("tag", "tags")
+	m.list.ResetSelected()
+	return m
+}
+
+// updatePickerKey handles keys while choosing a feed or tag to filter the
+// post screens by. It reports whether the key was consumed; everything else
+// goes to the list so the picker can be navigated and searched.
+func (m model) updatePickerKey(key string) (model, tea.Cmd, bool) {
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit, true
+
+	case "enter":
+		switch item := m.list.SelectedItem().(type) {
+		case feedItem:
+			feed := item.feed
+			m.feedFilter = &feed
+		case tagItem:
+			m.tagFilter = item.name
+		default:
+			return m, nil, true
+		}
+		m.picker = pickerNone
+		m.list.ResetFilter()
+		m.list.ResetSelected()
+		return m, m.reloadCmd(), true
+
+	case "esc", "q":
+		if m.list.FilterState() != list.Unfiltered {
+			return m, nil, false
+		}
+		m.picker = pickerNone
+		m.list.ResetSelected()
+		return m, m.reloadCmd(), true
+	}
+
+	return m, nil, false
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"context"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func newSearchInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = "words to find in titles, summaries and authors"
+	input.PlaceholderStyle = dateStyle
+	return input
+}
+
+// searchCmd runs a full-text search over all posts, not just the loaded
+// ones, and delivers the results like any other screen
+func searchCmd(ctx context.Context, queries *database.Queries, query string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		posts, err := queries.Search(ctx, query)
+		if err != nil {
+			return loadPostsMsg{err: err}
+		}
+
+		unread, err := queries.UnreadCount(ctx)
+		return loadPostsMsg{posts: posts, unread: unread, err: err}
+	}
+}
+
+// updateSearchInput handles keys while the search query is being typed
+func (m model) updateSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.typingSearch = false
+		m.searchInput.Blur()
+		return m, nil
+
+	case "enter":
+		m.typingSearch = false
+		m.searchInput.Blur()
+		m.searchQuery = m.searchInput.Value()
+		m = m.switchScreen(screenSearch)
+		m.list.ResetFilter()
+		m.list.ResetSelected()
+		return m, m.reloadCmd()
+	}
+
+	var cmd tea.Cmd
+	m.searchInput, cmd = m.searchInput.Update(msg)
+	return m, cmd
+}
+
+// homeScreen is the screen a post is listed on, going by its state
+func homeScreen(post database.PostWithFeed) screenType {
+	switch {
+	case post.IsStarred.Valid && post.IsStarred.Int64 == 1:
+		return screenStarred
+	case post.IsArchived.Valid && post.IsArchived.Int64 == 1:
+		return screenArchive
+	default:
+		return screenInbox
+	}
+}
+
+// jumpToPost leaves the search for the screen the post is on and selects
+// it there. Filters are cleared so they can't hide it; a snoozed post, or
+// one past the loaded page of the archive, leaves the cursor at the top.
+func (m model) jumpToPost(post database.PostWithFeed) (model, tea.Cmd) {
+	m = m.switchScreen(homeScreen(post))
+	m.feedFilter = nil
+	m.tagFilter = ""
+	m.pendingSelect = post.ID
+	m.list.ResetFilter()
+	m.list.ResetSelected()
+	return m, m.reloadCmd()
+}
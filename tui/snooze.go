@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aaronzipp/feeder/database"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snoozeChoice is one entry of the menu "z" opens
+type snoozeChoice struct {
+	key   string
+	label string
+	until func(now time.Time) time.Time
+}
+
+// morning returns 8:00 on the day days after now
+func morning(now time.Time, days int) time.Time {
+	return time.Date(now.Year(), now.Month(), now.Day()+days, 8, 0, 0, 0, now.Location())
+}
+
+var snoozeChoices = []snoozeChoice{
+	{"h", "in 3 hours", func(now time.Time) time.Time {
+		return now.Add(3 * time.Hour)
+	}},
+	{"t", "tomorrow", func(now time.Time) time.Time {
+		return morning(now, 1)
+	}},
+	{"w", "next week", func(now time.Time) time.Time {
+		// Monday morning, a full week ahead when it's Monday already
+		days := (8 - int(now.Weekday())) % 7
+		if days == 0 {
+			days = 7
+		}
+		return morning(now, days)
+	}},
+}
+
+type snoozePostMsg struct {
+	postID int64
+	until  time.Time
+	err    error
+}
+
+func snoozePostCmd(ctx context.Context, queries *database.Queries, postID int64, until time.Time) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		err := queries.Snooze(ctx, postID, until)
+		return snoozePostMsg{postID: postID, until: until, err: err}
+	}
+}
+
+// snoozePrompt lists the snooze choices for the title bar
+func snoozePrompt() string {
+	choices := make([]string, len(snoozeChoices))
+	for i, choice := range snoozeChoices {
+		choices[i] = choice.key + " " + choice.label
+	}
+	return "Snooze until? " + strings.Join(choices, " · ")
+}
+
+// updateSnooze resolves the snooze menu: a listed key snoozes the post, any
+// other key cancels
+func (m model) updateSnooze(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	post := *m.snoozing
+	m.snoozing = nil
+
+	for _, choice := range snoozeChoices {
+		if msg.String() == choice.key {
+			return m.removePost(post.ID), snoozePostCmd(m.ctx, m.queries, post.ID, choice.until(time.Now()))
+		}
+	}
+	return m, nil
+}
@@ -0,0 +1,119 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aaronzipp/feeder/database"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// tab is a screen in the tab bar, reached with its number key
+type tab struct {
+	key    string
+	screen screenType
+}
+
+var tabs = []tab{
+	{"1", screenInbox},
+	{"2", screenStarred},
+	{"3", screenArchive},
+	{"4", screenFeeds},
+	{"5", screenNew},
+}
+
+type screenCountsMsg struct {
+	counts database.CountScreensRow
+	err    error
+}
+
+func screenCountsCmd(ctx context.Context, queries *database.Queries) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		counts, err := queries.ScreenCounts(ctx)
+		return screenCountsMsg{counts: counts, err: err}
+	}
+}
+
+// icon is the emoji the screen is shown with
+func (s screenType) icon() string {
+	switch s {
+	case screenInbox:
+		return "📬"
+	case screenArchive:
+		return "📦"
+	case screenStarred:
+		return "⭐"
+	case screenFeeds:
+		return "📡"
+	case screenSearch:
+		return "🔍"
+	case screenNew:
+		return "✨"
+	default:
+		return ""
+	}
+}
+
+// count returns how many items the screen holds, if that is known; the
+// New screen isn't counted
+func (m model) count(screen screenType) (int64, bool) {
+	if m.counts == nil {
+		return 0, false
+	}
+	switch screen {
+	case screenInbox:
+		return m.counts.Inbox, true
+	case screenStarred:
+		return m.counts.Starred, true
+	case screenArchive:
+		return m.counts.Archive, true
+	case screenFeeds:
+		return m.counts.Feeds, true
+	default:
+		return 0, false
+	}
+}
+
+// tabBar renders a line with every screen, its key and how many items it
+// holds, highlighting the current one. Narrow terminals get the compact
+// form, "1 📬 42", without names.
+func (m model) tabBar() string {
+	render := func(compact bool) string {
+		parts := make([]string, len(tabs))
+		for i, tab := range tabs {
+			count, counted := m.count(tab.screen)
+			var label string
+			switch {
+			case compact && counted:
+				label = fmt.Sprintf("%s %s %d", tab.key, tab.screen.icon(), count)
+			case compact:
+				label = tab.key + " " + tab.screen.icon()
+			default:
+				name := tab.screen.String()
+				label = fmt.Sprintf("[%s] %s %s", tab.key, tab.screen.icon(), strings.ToUpper(name[:1])+name[1:])
+				if counted {
+					label += fmt.Sprintf(" (%d)", count)
+				}
+			}
+
+			style := dateStyle
+			if tab.screen == m.currentScreen {
+				style = activeTabStyle
+			}
+			parts[i] = style.Render(label)
+		}
+		return "  " + strings.Join(parts, "  ")
+	}
+
+	bar := render(false)
+	if lipgloss.Width(bar) > m.width {
+		bar = ansi.Truncate(render(true), m.width, "…")
+	}
+	return bar
+}
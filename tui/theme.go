@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultTheme is the color scheme used unless another is configured
+const DefaultTheme = "tokyonight"
+
+// Theme names the colors the TUI is drawn with
+type Theme struct {
+	// Text is used for post titles and descriptions
+	Text lipgloss.TerminalColor
+	// Accent marks feed names and headings
+	Accent lipgloss.TerminalColor
+	// Selected is the item under the cursor
+	Selected lipgloss.TerminalColor
+	// Muted is used for dates, hints and read posts
+	Muted lipgloss.TerminalColor
+	// Warning flags fetch errors and failed actions
+	Warning lipgloss.TerminalColor
+	// Cursor draws the cursor, marks and the active tab
+	Cursor lipgloss.TerminalColor
+}
+
+var themes = map[string]Theme{
+	"tokyonight": {
+		Text:     lipgloss.Color("#7aa2f7"), // blue
+		Accent:   lipgloss.Color("#bb9af7"), // purple
+		Selected: lipgloss.Color("#9ece6a"), // green
+		Muted:    lipgloss.Color("#565f89"), // comment
+		Warning:  lipgloss.Color("#e0af68"), // yellow
+		Cursor:   lipgloss.Color("#f7768e"), // red
+	},
+	"gruvbox": {
+		Text:     lipgloss.Color("#83a598"), // blue
+		Accent:   lipgloss.Color("#d3869b"), // purple
+		Selected: lipgloss.Color("#b8bb26"), // green
+		Muted:    lipgloss.Color("#928374"), // gray
+		Warning:  lipgloss.Color("#fabd2f"), // yellow
+		Cursor:   lipgloss.Color("#fb4934"), // red
+	},
+	"solarized": {
+		Text:     lipgloss.Color("#268bd2"), // blue
+		Accent:   lipgloss.Color("#6c71c4"), // violet
+		Selected: lipgloss.Color("#859900"), // green
+		Muted:    lipgloss.Color("#586e75"), // base01
+		Warning:  lipgloss.Color("#b58900"), // yellow
+		Cursor:   lipgloss.Color("#dc322f"), // red
+	},
+	// nocolor keeps only bold text, for NO_COLOR and terminals where the
+	// other themes are hard to read
+	"nocolor": {
+		Text:     lipgloss.NoColor{},
+		Accent:   lipgloss.NoColor{},
+		Selected: lipgloss.NoColor{},
+		Muted:    lipgloss.NoColor{},
+		Warning:  lipgloss.NoColor{},
+		Cursor:   lipgloss.NoColor{},
+	},
+}
+
+// LookupTheme returns the theme called name
+func LookupTheme(name string) (Theme, error) {
+	theme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		names := slices.Sorted(maps.Keys(themes))
+		return Theme{}, fmt.Errorf("unknown theme %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return theme, nil
+}
+
+var (
+	titleStyle       lipgloss.Style
+	feedNameStyle    lipgloss.Style
+	selectedStyle    lipgloss.Style
+	dateStyle        lipgloss.Style
+	feedErrorStyle   lipgloss.Style
+	cursorStyle      lipgloss.Style
+	tooSmallStyle    lipgloss.Style
+	detailTitleStyle lipgloss.Style
+	detailHintStyle  lipgloss.Style
+	helpKeyStyle     lipgloss.Style
+	activeTabStyle   lipgloss.Style
+)
+
+func init() {
+	useTheme(themes[DefaultTheme])
+}
+
+// useTheme sets every style from theme. It has to run before the model is
+// built, since some components copy the styles they're given.
+func useTheme(theme Theme) {
+	titleStyle = lipgloss.NewStyle().
+		Foreground(theme.Text)
+
+	feedNameStyle = lipgloss.NewStyle().
+		Foreground(theme.Accent).
+		Bold(true)
+
+	selectedStyle = lipgloss.NewStyle().
+		Foreground(theme.Selected)
+
+	dateStyle = lipgloss.NewStyle().
+		Foreground(theme.Muted)
+
+	feedErrorStyle = lipgloss.NewStyle().
+		Foreground(theme.Warning).
+		Bold(true)
+
+	cursorStyle = lipgloss.NewStyle().
+		Foreground(theme.Cursor).
+		Bold(true)
+
+	tooSmallStyle = dateStyle.
+		Align(lipgloss.Center)
+
+	detailTitleStyle = titleStyle.
+		Bold(true).
+		MarginBottom(1)
+
+	detailHintStyle = dateStyle.
+		MarginTop(1)
+
+	helpKeyStyle = cursorStyle.
+		Bold(false)
+
+	activeTabStyle = cursorStyle
+}
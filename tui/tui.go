@@ -6,41 +6,77 @@ import (
 	"io"
 	"os/exec"
 	"runtime"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
-var (
-	// Tokyo Night Dark color scheme
-	titleStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#7aa2f7")) // Tokyo Night blue
+// postItem implements list.Item and list.DefaultItem interfaces
+type postItem struct {
+	post database.PostWithFeed
+}
 
-	feedNameStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#bb9af7")). // Tokyo Night purple
-			Bold(true)
+func (i postItem) isRead() bool {
+	return i.post.IsRead.Valid && i.post.IsRead.Int64 == 1
+}
 
-	selectedStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#9ece6a")) // Tokyo Night green
+func (i postItem) dateEstimated() bool {
+	return i.post.DateEstimated.Valid && i.post.DateEstimated.Int64 == 1
+}
 
-	dateStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#565f89")) // Tokyo Night comment
+func (i postItem) isStarred() bool {
+	return i.post.IsStarred.Valid && i.post.IsStarred.Int64 == 1
+}
 
-	cursorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#f7768e")). // Tokyo Night red
-			Bold(true)
-)
+func (i postItem) isArchived() bool {
+	return i.post.IsArchived.Valid && i.post.IsArchived.Int64 == 1
+}
 
-// postItem implements list.Item and list.DefaultItem interfaces
-type postItem struct {
-	post database.PostWithFeed
+// stateMark flags what the current screen doesn't already say about the
+// post: a star outside Starred, and a box for archived posts outside Archive.
+// Search mixes every state, so inbox posts get an empty box there.
+func (i postItem) stateMark(screen screenType) string {
+	switch {
+	case i.isStarred() && screen != screenStarred:
+		return "★"
+	case i.isArchived() && screen != screenArchive:
+		return "▣"
+	case screen == screenSearch:
+		return "□"
+	default:
+		return ""
+	}
+}
+
+// dateLabel is the post's relative date, or "unknown date" when the feed
+// gave none and the stored date is only used for sorting
+func (i postItem) dateLabel() string {
+	if i.dateEstimated() {
+		return "unknown date"
+	}
+	return formatDate(i.post.PublishedAt)
+}
+
+// feedLabel is the feed name, flagged when the feed's last fetch failed
+func (i postItem) feedLabel() string {
+	if i.post.FeedError.Valid {
+		return "⚠ " + i.post.FeedName
+	}
+	return i.post.FeedName
 }
 
 func (i postItem) FilterValue() string {
-	return i.post.Title + " " + i.post.FeedName
+	// Include the author so the text filter can find posts by writer
+	return i.post.Title + " " + i.post.FeedName + " " + i.post.Author.String
 }
 
 func (i postItem) Title() string {
@@ -48,12 +84,40 @@ func (i postItem) Title() string {
 }
 
 func (i postItem) Description() string {
-	return i.post.FeedName + " • " + formatDate(i.post.PublishedAt)
+	return i.post.FeedName + " • " + i.dateLabel()
 }
 
 // customDelegate renders items with Tokyo Night colors and tabular format
 type customDelegate struct {
 	list.DefaultDelegate
+	screen     screenType
+	markWidth  int
+	titleWidth int
+	feedWidth  int
+	dateWidth  int
+	// durationWidth is 0 unless some post is an episode of known length
+	durationWidth int
+}
+
+// newDelegate sizes the columns once for all items, so they don't shift while
+// scrolling and Render doesn't have to rescan the list for every row. The
+// feed column is capped at maxFeedWidth, unless that is 0, so one verbose
+// feed name doesn't squeeze every title.
+func newDelegate(items []list.Item, screen screenType, maxFeedWidth int) customDelegate {
+	d := customDelegate{screen: screen}
+	for _, item := range items {
+		if pi, ok := item.(postItem); ok {
+			d.markWidth = max(d.markWidth, lipgloss.Width(pi.stateMark(screen)))
+			d.titleWidth = max(d.titleWidth, lipgloss.Width(pi.post.Title))
+			d.feedWidth = max(d.feedWidth, lipgloss.Width(pi.feedLabel()))
+			d.dateWidth = max(d.dateWidth, lipgloss.Width(pi.dateLabel()))
+			d.durationWidth = max(d.durationWidth, lipgloss.Width(durationLabel(pi.post)))
+		}
+	}
+	if maxFeedWidth > 0 {
+		d.feedWidth = min(d.feedWidth, maxFeedWidth)
+	}
+	return d
 }
 
 func (d customDelegate) Height() int {
@@ -74,62 +138,66 @@ func (d customDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 		return
 	}
 
-	// Calculate column widths based on visible items
-	maxTitleWidth := 0
-	maxFeedWidth := 0
-	maxDateWidth := 0
+	maxTitleWidth := d.titleWidth
+	maxFeedWidth := d.feedWidth
+	maxDateWidth := d.dateWidth
 
-	for _, visibleItem := range m.VisibleItems() {
-		if vi, ok := visibleItem.(postItem); ok {
-			titleLen := len(vi.post.Title)
-			feedLen := len(vi.post.FeedName)
-			dateLen := len(formatDate(vi.post.PublishedAt))
+	// The mark column only takes space when some post has a mark
+	mark := ""
+	if d.markWidth > 0 {
+		mark = cursorStyle.Render(padRight(i.stateMark(d.screen), d.markWidth)) + " "
+	}
 
-			if titleLen > maxTitleWidth {
-				maxTitleWidth = titleLen
-			}
-			if feedLen > maxFeedWidth {
-				maxFeedWidth = feedLen
-			}
-			if dateLen > maxDateWidth {
-				maxDateWidth = dateLen
-			}
-		}
+	// Like the mark, the duration column only appears for episodes
+	duration := ""
+	if d.durationWidth > 0 {
+		duration = "  " + dateStyle.Render(padRight(durationLabel(i.post), d.durationWidth))
 	}
 
-	// Reserve space for cursor and spacing
-	availableWidth := m.Width() - 2 - 8
+	// Reserve space for cursor, mark and spacing. Narrow terminals shrink
+	// the title down to 20 columns first, then the feed name.
+	availableWidth := max(0, m.Width()-2-8-lipgloss.Width(mark)-lipgloss.Width(duration))
 	if maxTitleWidth > availableWidth-maxFeedWidth-maxDateWidth {
 		maxTitleWidth = max(20, availableWidth-maxFeedWidth-maxDateWidth)
 	}
+	maxFeedWidth = max(0, min(maxFeedWidth, availableWidth-maxTitleWidth-maxDateWidth))
 
 	cursor := "  "
 	if index == m.Index() {
 		cursor = cursorStyle.Render("❯ ")
 	}
 
-	// Truncate title if needed
-	title := i.post.Title
-	if len(title) > maxTitleWidth {
-		title = title[:maxTitleWidth-3] + "..."
-	}
+	// Truncate title if needed, by display width so multibyte and wide
+	// characters are never cut in half
+	title := ansi.Truncate(i.post.Title, maxTitleWidth, "...")
 
 	// Format with fixed-width columns
-	titlePadded := fmt.Sprintf("%-*s", maxTitleWidth, title)
-	feedPadded := fmt.Sprintf("%-*s", maxFeedWidth, i.post.FeedName)
-	datePadded := fmt.Sprintf("%-*s", maxDateWidth, formatDate(i.post.PublishedAt))
+	titlePadded := padRight(title, maxTitleWidth)
+	feedPadded := padRight(ansi.Truncate(i.feedLabel(), maxFeedWidth, "…"), maxFeedWidth)
+	datePadded := padRight(i.dateLabel(), maxDateWidth)
 
 	// Apply styles
 	var styledTitle string
 	if index == m.Index() {
 		styledTitle = selectedStyle.Render(titlePadded)
+	} else if i.isRead() {
+		// Dim read posts so unread ones stand out
+		styledTitle = dateStyle.Render(titlePadded)
 	} else {
 		styledTitle = titleStyle.Render(titlePadded)
 	}
 	styledFeed := feedNameStyle.Render(feedPadded)
+	if i.post.FeedError.Valid {
+		styledFeed = feedErrorStyle.Render(feedPadded)
+	}
 	styledDate := dateStyle.Render(datePadded)
 
-	fmt.Fprint(w, cursor+styledTitle+"  "+styledFeed+"  "+styledDate)
+	fmt.Fprint(w, cursor+mark+styledTitle+"  "+styledFeed+"  "+styledDate+duration)
+}
+
+// padRight pads s with spaces up to the given display width
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(0, width-lipgloss.Width(s)))
 }
 
 type screenType int
@@ -138,6 +206,9 @@ const (
 	screenInbox screenType = iota
 	screenArchive
 	screenStarred
+	screenFeeds
+	screenSearch
+	screenNew
 )
 
 func (s screenType) String() string {
@@ -148,14 +219,25 @@ func (s screenType) String() string {
 		return "archive"
 	case screenStarred:
 		return "starred"
+	case screenFeeds:
+		return "feeds"
+	case screenSearch:
+		return "search"
+	case screenNew:
+		return "new"
 	default:
 		return "unknown"
 	}
 }
 
 type loadPostsMsg struct {
-	posts []database.PostWithFeed
-	err   error
+	posts  []database.PostWithFeed
+	unread database.UnreadCountRow
+	// since is the start of the New screen, zero when never caught up
+	since time.Time
+	// more is set when the list was cut off at its limit
+	more bool
+	err  error
 }
 
 type archivePostMsg struct {
@@ -163,6 +245,11 @@ type archivePostMsg struct {
 	err    error
 }
 
+type archiveAllMsg struct {
+	archived int64
+	err      error
+}
+
 type unarchivePostMsg struct {
 	postID int64
 	err    error
@@ -178,41 +265,238 @@ type unstarPostMsg struct {
 	err    error
 }
 
+type markReadMsg struct {
+	postID int64
+	err    error
+}
+
+type markUnreadMsg struct {
+	postID int64
+	err    error
+}
+
+type refreshDoneMsg struct {
+	err error
+}
+
+type openBrowserMsg struct {
+	postID int64
+	err    error
+}
+
+// tickMsg redraws the screen so relative dates such as "today" move on
+// while the TUI is left open
+type tickMsg struct{}
+
+// tickInterval is how often relative dates are brought up to date
+const tickInterval = time.Minute
+
+func tickCmd() tea.Cmd {
+	return tea.Tick(tickInterval, func(time.Time) tea.Msg {
+		return tickMsg{}
+	})
+}
+
+type caughtUpMsg struct {
+	err error
+}
+
+type viewState int
+
+const (
+	viewList viewState = iota
+	viewDetail
+	viewHelp
+)
+
 type model struct {
-	list          list.Model
-	currentScreen screenType
-	queries       *database.Queries
-	ctx           context.Context
-	lastKey       string
+	list              list.Model
+	currentScreen     screenType
+	queries           *database.Queries
+	ctx               context.Context
+	lastKey           string
+	view              viewState
+	detailPost        database.PostWithFeed
+	detailContent     string
+	detailOffset      int
+	width             int
+	height            int
+	refreshing        bool
+	spinner           spinner.Model
+	pendingDelete     *database.Feed
+	sort              database.PostSort
+	picker            pickerKind
+	feedFilter        *database.Feed
+	tagFilter         string
+	confirmArchiveAll bool
+	confirmOpenAll    bool
+	fetchOpts         fetch.Options
+	searchInput       textinput.Model
+	typingSearch      bool
+	searchQuery       string
+	newSince          time.Time
+	openErr           string
+	lastAction        *undoAction
+	snoozing          *database.PostWithFeed
+	// browser is the configured browser command split into its arguments,
+	// empty to use the platform's default handler
+	browser []string
+	// archiveLimit is how many archived posts are loaded, growing a page
+	// at a time as the cursor nears the end
+	archiveLimit int
+	morePosts    bool
+	loadingMore  bool
+	// pendingSelect is a post to select once the list is loaded, such as
+	// the one selected when the TUI was last closed
+	pendingSelect int64
+	// archiveOnOpen is a post to archive once it opened in the browser
+	archiveOnOpen int64
+	// counts are shown in the tab bar, nil until first loaded
+	counts *database.CountScreensRow
+	// feedWidth caps the feed column of the post list, 0 for no cap
+	feedWidth int
+	// readingMode makes enter open the post, archive it and move on to
+	// the next unread one, for going through the inbox a key at a time
+	readingMode bool
+	// deletingPost waits for confirmation to be deleted for good, twice
+	// over when it's starred: starredConfirmed is set after the first
+	deletingPost     *database.PostWithFeed
+	starredConfirmed bool
+	// detailBody is the reading pane's text wrapped to its width, kept
+	// so long posts aren't wrapped again on every key and redraw
+	detailBody wrappedBody
 }
 
-func loadPostsCmd(ctx context.Context, queries *database.Queries, screen screenType) tea.Cmd {
+func loadPostsCmd(
+	ctx context.Context,
+	queries *database.Queries,
+	screen screenType,
+	opts database.ListOptions,
+) tea.Cmd {
 	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
 		var posts []database.PostWithFeed
+		var since time.Time
 		var err error
 
 		switch screen {
 		case screenInbox:
-			posts, err = queries.ListInbox(ctx)
+			posts, err = queries.ListInbox(ctx, opts)
 		case screenArchive:
-			posts, err = queries.ListArchive(ctx)
+			posts, err = queries.ListArchive(ctx, opts)
 		case screenStarred:
-			posts, err = queries.ListStarred(ctx)
+			posts, err = queries.ListStarred(ctx, opts)
+		case screenNew:
+			since, err = queries.LastOpened(ctx)
+			if err != nil {
+				break
+			}
+			// Without a mark yet, show what arrived today
+			from := since
+			if from.IsZero() {
+				from = time.Now().Add(-24 * time.Hour)
+			}
+			posts, err = queries.ListNew(ctx, from, opts)
+		}
+		if err != nil {
+			return loadPostsMsg{err: err}
 		}
 
-		return loadPostsMsg{posts: posts, err: err}
+		unread, err := queries.UnreadCount(ctx)
+		more := opts.Limit > 0 && len(posts) == opts.Limit
+		return loadPostsMsg{posts: posts, unread: unread, since: since, more: more, err: err}
 	}
 }
 
+// reloadCmd reloads whatever the current screen shows
+func (m model) reloadCmd() tea.Cmd {
+	// Anything that changes a list may change the counts in the tab bar
+	return tea.Batch(m.loadCmd(), screenCountsCmd(m.ctx, m.queries))
+}
+
+// loadCmd loads what the current screen or picker lists
+func (m model) loadCmd() tea.Cmd {
+	if m.currentScreen == screenFeeds || m.picker == pickerFeed {
+		return loadFeedsCmd(m.ctx, m.queries)
+	}
+	if m.picker == pickerTag {
+		// Tags come from the posts that were loaded before picking
+		return nil
+	}
+	if m.currentScreen == screenSearch {
+		return searchCmd(m.ctx, m.queries, m.searchQuery)
+	}
+	opts := m.listOptions()
+	if m.currentScreen == screenArchive {
+		// Keep every page that was loaded so the cursor stays put
+		opts.Limit = max(m.archiveLimit, archivePageSize)
+	}
+	return loadPostsCmd(m.ctx, m.queries, m.currentScreen, opts)
+}
+
+// filterable reports whether the current screen lists posts that the feed
+// and tag filters and the sort order apply to
+func (m model) filterable() bool {
+	switch m.currentScreen {
+	case screenInbox, screenArchive, screenStarred, screenNew:
+		return true
+	default:
+		return false
+	}
+}
+
+// listOptions returns the sort and filters that apply to every post screen
+func (m model) listOptions() database.ListOptions {
+	opts := database.ListOptions{Sort: m.sort}
+	if m.feedFilter != nil {
+		opts.FeedID = m.feedFilter.ID
+	}
+	opts.Category = m.tagFilter
+	return opts
+}
+
 func archivePostCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
 	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
 		err := queries.ArchivePost(ctx, postID)
 		return archivePostMsg{postID: postID, err: err}
 	}
 }
 
+// readAndArchiveCmd marks a post that was opened as read and archives it,
+// reloading once for both
+func readAndArchiveCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		err := queries.MarkRead(ctx, postID)
+		if err == nil {
+			err = queries.ArchivePost(ctx, postID)
+		}
+		return archivePostMsg{postID: postID, err: err}
+	}
+}
+
+func archiveAllCmd(ctx context.Context, queries *database.Queries, opts database.ListOptions) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		archived, err := queries.ArchiveInbox(ctx, opts)
+		return archiveAllMsg{archived: archived, err: err}
+	}
+}
+
 func unarchivePostCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
 	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
 		err := queries.UnarchivePost(ctx, postID)
 		return unarchivePostMsg{postID: postID, err: err}
 	}
@@ -220,101 +504,146 @@ func unarchivePostCmd(ctx context.Context, queries *database.Queries, postID int
 
 func starPostCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
 	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
 		err := queries.StarPost(ctx, postID)
 		return starPostMsg{postID: postID, err: err}
 	}
 }
 
-func unstarPostCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
+// unstarPostCmd unstars a post, archiving it too when archive is set as the
+// Starred screen does, since a post is done with by then
+func unstarPostCmd(ctx context.Context, queries *database.Queries, postID int64, archive bool) tea.Cmd {
 	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
 		if err := queries.UnstarPost(ctx, postID); err != nil {
 			return unstarPostMsg{postID: postID, err: err}
 		}
 
-		if err := queries.ArchivePost(ctx, postID); err != nil {
-			return unstarPostMsg{postID: postID, err: err}
+		if archive {
+			if err := queries.ArchivePost(ctx, postID); err != nil {
+				return unstarPostMsg{postID: postID, err: err}
+			}
 		}
 
 		return unstarPostMsg{postID: postID, err: nil}
 	}
 }
 
+func markReadCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		err := queries.MarkRead(ctx, postID)
+		return markReadMsg{postID: postID, err: err}
+	}
+}
+
+func markUnreadCmd(ctx context.Context, queries *database.Queries, postID int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		err := queries.MarkUnread(ctx, postID)
+		return markUnreadMsg{postID: postID, err: err}
+	}
+}
+
+// caughtUpCmd moves the start of the New screen to now
+func caughtUpCmd(ctx context.Context, queries *database.Queries) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := dbContext(ctx)
+		defer cancel()
+
+		err := queries.SetLastOpened(ctx, time.Now())
+		return caughtUpMsg{err: err}
+	}
+}
+
+// refreshCmd fetches all feeds in the command goroutine so the UI stays
+// responsive while the network requests run
+func refreshCmd(ctx context.Context, queries *database.Queries, opts fetch.Options) tea.Cmd {
+	return func() tea.Msg {
+		_, err := fetch.Refresh(ctx, queries, opts)
+		return refreshDoneMsg{err: err}
+	}
+}
+
 func InitialModel(
 	ctx context.Context,
 	queries *database.Queries,
+	fetchOpts fetch.Options,
+	sort database.PostSort,
+	browserCommand string,
+	feedWidth int,
 	posts []database.PostWithFeed,
+	unread database.UnreadCountRow,
 ) model {
 	items := make([]list.Item, len(posts))
 	for i, post := range posts {
 		items[i] = postItem{post: post}
 	}
 
-	delegate := customDelegate{}
-
-	l := list.New(items, delegate, 0, 0)
+	l := list.New(items, newDelegate(items, screenInbox, feedWidth), 0, 0)
 	l.Styles.Title = lipgloss.NewStyle()
 	l.SetShowStatusBar(true)
+	l.SetStatusBarItemName("post"+unreadStatus(unread), "posts"+unreadStatus(unread))
 	l.SetShowHelp(true)
 	l.SetFilteringEnabled(true)
 	l.DisableQuitKeybindings()
+	// "?" opens the full help overlay instead of the list's own help
+	l.KeyMap.ShowFullHelp.SetHelp("?", "all keys")
+	// Long enough to read a URL that couldn't be copied
+	l.StatusMessageLifetime = 3 * time.Second
 
 	// Remove background color from title
 	l.Styles.Title = lipgloss.NewStyle()
 
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = dateStyle
+
 	return model{
 		list:          l,
 		currentScreen: screenInbox,
 		queries:       queries,
 		ctx:           ctx,
 		lastKey:       "",
+		spinner:       s,
+		sort:          sort,
+		fetchOpts:     fetchOpts,
+		searchInput:   newSearchInput(),
+		browser:       strings.Fields(browserCommand),
+		feedWidth:     feedWidth,
 	}
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	// Only the inbox is loaded up front, a restored screen loads now
+	if m.currentScreen != screenInbox {
+		return tea.Batch(tickCmd(), m.reloadCmd())
+	}
+	return tea.Batch(tickCmd(), screenCountsCmd(m.ctx, m.queries))
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
-		// Calculate height needed for exactly 10 items per page
-		itemHeight := 1  // from delegate.Height()
-		itemSpacing := 1 // from delegate.Spacing()
-		desiredItems := 10
-
-		// Calculate chrome height based on enabled components
-		// The list component's updatePagination() subtracts these from available height:
-		// - statusView: typically 1 line when shown
-		// - paginationView: typically 1 line when shown
-		// - helpView: typically 2-3 lines when shown
-		// We add 1 extra for safe padding
-		chromeHeight := 0
-		if m.list.ShowStatusBar() {
-			chromeHeight += 1
-		}
-		// Pagination is shown by default
-		chromeHeight += 1
-		if m.list.ShowHelp() {
-			chromeHeight += 3 // Help view can be multiple lines
-		}
-		chromeHeight += 1 // Extra padding for safety
-
-		// Calculate the height that would give us exactly 10 items
-		// The formula mirrors what updatePagination does:
-		// availHeight = height - chrome
-		// PerPage = availHeight / (itemHeight + spacing)
-		// So: height = (PerPage * (itemHeight + spacing)) + chrome
-		itemsHeight := desiredItems * (itemHeight + itemSpacing)
-		constrainedHeight := itemsHeight + chromeHeight
-
-		// Use the smaller of terminal height or our constrained height
-		height := min(msg.Height, constrainedHeight)
-
-		m.list.SetSize(msg.Width, height)
+		// Let the list fill the window below the tab bar; it works out how
+		// many items fit on a page from its own title, status bar,
+		// pagination and help
+		m.list.SetSize(msg.Width, msg.Height-1)
+		m.width = msg.Width
+		m.height = msg.Height
+		m = m.wrapDetailBody()
 
 	case loadPostsMsg:
 		if msg.err != nil {
-			return m, nil
+			return m.dbErrorStatus(msg.err)
 		}
 		oldCursor := m.list.Index()
 
@@ -324,44 +653,252 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			items[i] = postItem{post: post}
 		}
 		m.list.SetItems(items)
+		m.list.SetDelegate(newDelegate(items, m.currentScreen, m.feedWidth))
+		m.newSince = msg.since
+		m.morePosts = msg.more
+		m.loadingMore = false
+		m = m.setUnreadStatus(msg.unread)
 
 		if oldCursor >= len(items) && len(items) > 0 {
 			m.list.Select(len(items) - 1)
 		} else {
 			m.list.Select(oldCursor)
 		}
+		m = m.selectPendingPost()
 
 		return m, nil
 
+	case unreadCountMsg:
+		// Like the tab counts, a stale summary beats an error
+		if msg.err == nil {
+			m = m.setUnreadStatus(msg.unread)
+		}
+		return m, nil
+
+	case screenCountsMsg:
+		// Stale counts beat an error on every reload; the list's own
+		// load reports database trouble
+		if msg.err == nil {
+			m.counts = &msg.counts
+		}
+		return m, nil
+
+	case loadMoreMsg:
+		if msg.err != nil {
+			m.loadingMore = false
+			return m.dbErrorStatus(msg.err)
+		}
+		return m.appendPosts(msg), nil
+
 	case archivePostMsg:
 		if msg.err != nil {
-			return m, nil
+			return m.postWriteFailed(msg.err)
+		}
+		return m, m.postWrittenCmd()
+
+	case deletePostMsg:
+		if msg.err != nil {
+			return m.postWriteFailed(msg.err)
+		}
+		return m, m.postWrittenCmd()
+
+	case archiveAllMsg:
+		if msg.err != nil {
+			return m.dbErrorStatus(msg.err)
 		}
 		// Reload the current screen to reflect the change
-		return m, loadPostsCmd(m.ctx, m.queries, m.currentScreen)
+		return m, m.reloadCmd()
 
 	case unarchivePostMsg:
 		if msg.err != nil {
-			return m, nil
+			return m.postWriteFailed(msg.err)
 		}
-		// Reload the current screen to reflect the change
-		return m, loadPostsCmd(m.ctx, m.queries, m.currentScreen)
+		return m, m.postWrittenCmd()
 
 	case starPostMsg:
 		if msg.err != nil {
-			return m, nil
+			return m.postWriteFailed(msg.err)
 		}
-		// Reload the current screen to reflect the change
-		return m, loadPostsCmd(m.ctx, m.queries, m.currentScreen)
+		return m, m.postWrittenCmd()
 
 	case unstarPostMsg:
 		if msg.err != nil {
+			return m.postWriteFailed(msg.err)
+		}
+		return m, m.postWrittenCmd()
+
+	case loadFeedsMsg:
+		if msg.err != nil {
+			return m.dbErrorStatus(msg.err)
+		}
+		oldCursor := m.list.Index()
+
+		items := make([]list.Item, len(msg.feeds))
+		for i, feed := range msg.feeds {
+			items[i] = feedItem{feed: feed}
+		}
+		m.list.SetItems(items)
+		m.list.SetDelegate(newFeedDelegate(items))
+		m.list.SetStatusBarItemName("feed", "feeds")
+
+		if oldCursor >= len(items) && len(items) > 0 {
+			m.list.Select(len(items) - 1)
+		} else {
+			m.list.Select(oldCursor)
+		}
+
+		return m, nil
+
+	case deleteFeedMsg:
+		if msg.err != nil {
+			return m.dbErrorStatus(msg.err)
+		}
+		// Reload the current screen to reflect the change
+		return m, m.reloadCmd()
+
+	case archiveFeedMsg:
+		if msg.err != nil {
+			return m.dbErrorStatus(msg.err)
+		}
+		text := fmt.Sprintf("Archived %d posts from %s", msg.archived, msg.feed)
+		return m, tea.Batch(m.reloadCmd(), m.list.NewStatusMessage(dateStyle.Render(text)))
+
+	case setFeedEnabledMsg:
+		if msg.err != nil {
+			return m.dbErrorStatus(msg.err)
+		}
+		// Reload the current screen to reflect the change
+		return m, m.reloadCmd()
+
+	case setFeedIntervalMsg:
+		if msg.err != nil {
+			return m.dbErrorStatus(msg.err)
+		}
+		// Reload the current screen to reflect the change
+		return m, m.reloadCmd()
+
+	case refreshDoneMsg:
+		m.refreshing = false
+		// Reload even on error, since some feeds may have been stored
+		return m, m.reloadCmd()
+
+	case spinner.TickMsg:
+		if !m.refreshing {
 			return m, nil
 		}
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+
+	case markReadMsg:
+		if msg.err != nil {
+			return m.postWriteFailed(msg.err)
+		}
+		return m, m.postWrittenCmd()
+
+	case markUnreadMsg:
+		if msg.err != nil {
+			return m.postWriteFailed(msg.err)
+		}
+		return m, m.postWrittenCmd()
+
+	case openBrowserMsg:
+		archive := msg.postID == m.archiveOnOpen
+		m.archiveOnOpen = 0
+		if msg.err != nil {
+			m.openErr = "Can't open browser: " + msg.err.Error()
+			return m, m.list.NewStatusMessage(feedErrorStyle.Render(m.openErr))
+		}
+		m.openErr = ""
+		post, listed := m.listedPost(msg.postID)
+		if archive {
+			// Undoing unarchives the post but leaves it read
+			post.ID = msg.postID
+			asRead(&post)
+			m.lastAction = &undoAction{kind: undoArchive, post: post, index: m.postIndex(post.ID)}
+			m.pendingSelect = m.nextPostID(msg.postID, m.readingMode)
+			write := readAndArchiveCmd(m.ctx, m.queries, msg.postID)
+			if !listed {
+				return m, write
+			}
+			return m.changePost(post, asArchived, write)
+		}
+		write := markReadCmd(m.ctx, m.queries, msg.postID)
+		if !listed {
+			return m, write
+		}
+		return m.changePost(post, asRead, write)
+
+	case postContentMsg:
+		if msg.err != nil {
+			return m.dbErrorStatus(msg.err)
+		}
+		// The pane may have moved on to another post meanwhile
+		if msg.postID == m.detailPost.ID {
+			m.detailContent = msg.content
+			m = m.wrapDetailBody()
+		}
+		return m, nil
+
+	case copyURLMsg:
+		// Without a clipboard, show the URL so it can be copied by hand
+		if msg.err != nil {
+			return m, m.list.NewStatusMessage(dateStyle.Render("No clipboard, URL: ") + msg.url)
+		}
+		return m, m.list.NewStatusMessage(dateStyle.Render("Copied " + msg.url))
+
+	case tickMsg:
+		// Nothing to change, returning is enough to redraw
+		return m, tickCmd()
+
+	case snoozePostMsg:
+		if msg.err != nil {
+			return m.postWriteFailed(msg.err)
+		}
+		status := m.list.NewStatusMessage(dateStyle.Render("Snoozed until " + msg.until.Format("Mon Jan 2 15:04")))
+		return m, tea.Batch(m.postWrittenCmd(), status)
+
+	case caughtUpMsg:
+		if msg.err != nil {
+			return m.dbErrorStatus(msg.err)
+		}
 		// Reload the current screen to reflect the change
-		return m, loadPostsCmd(m.ctx, m.queries, m.currentScreen)
+		return m, m.reloadCmd()
 
 	case tea.KeyMsg:
+		if m.view == viewDetail {
+			return m.updateDetail(msg)
+		}
+		if m.view == viewHelp {
+			return m.updateHelp(msg)
+		}
+		if m.pendingDelete != nil {
+			return m.updateConfirmDelete(msg)
+		}
+		if m.deletingPost != nil {
+			return m.updateConfirmPostDelete(msg)
+		}
+		if m.snoozing != nil {
+			return m.updateSnooze(msg)
+		}
+		if m.typingSearch {
+			return m.updateSearchInput(msg)
+		}
+		if m.confirmArchiveAll {
+			m.confirmArchiveAll = false
+			if msg.String() == "y" {
+				return m, archiveAllCmd(m.ctx, m.queries, m.listOptions())
+			}
+			return m, nil
+		}
+		if m.confirmOpenAll {
+			m.confirmOpenAll = false
+			if msg.String() == "y" {
+				return m, m.openAllCmd(m.visiblePosts())
+			}
+			return m, nil
+		}
+
 		key := msg.String()
 
 		// Filter guard: only intercept keys when NOT filtering
@@ -373,6 +910,19 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}()
 			}
 
+			if m.picker != pickerNone {
+				if updated, cmd, handled := m.updatePickerKey(key); handled {
+					return updated, cmd
+				}
+				break
+			}
+
+			if m.currentScreen == screenFeeds {
+				if updated, cmd, handled := m.updateFeedsKey(key); handled {
+					return updated, cmd
+				}
+			}
+
 			switch key {
 			case "ctrl+c", "q":
 				return m, tea.Quit
@@ -389,66 +939,239 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 			case "1":
 				if m.currentScreen != screenInbox {
-					m.currentScreen = screenInbox
-					return m, loadPostsCmd(m.ctx, m.queries, screenInbox)
+					m = m.switchScreen(screenInbox)
+					return m, m.reloadCmd()
 				}
 
 			case "2":
 				if m.currentScreen != screenStarred {
-					m.currentScreen = screenStarred
-					return m, loadPostsCmd(m.ctx, m.queries, screenStarred)
+					m = m.switchScreen(screenStarred)
+					return m, m.reloadCmd()
 				}
 
 			case "3":
 				if m.currentScreen != screenArchive {
-					m.currentScreen = screenArchive
-					return m, loadPostsCmd(m.ctx, m.queries, screenArchive)
+					m = m.switchScreen(screenArchive)
+					return m, m.reloadCmd()
+				}
+
+			case "4":
+				if m.currentScreen != screenFeeds {
+					m = m.switchScreen(screenFeeds)
+					return m, m.reloadCmd()
+				}
+
+			case "5":
+				if m.currentScreen != screenNew {
+					m = m.switchScreen(screenNew)
+					return m, m.reloadCmd()
+				}
+
+			case "c":
+				// The New screen only moves on when asked to
+				if m.currentScreen == screenNew {
+					return m, caughtUpCmd(m.ctx, m.queries)
+				}
+
+			case "f":
+				if m.filterable() {
+					m.picker = pickerFeed
+					m.list.ResetFilter()
+					m.list.ResetSelected()
+					return m, m.reloadCmd()
+				}
+
+			case "S":
+				m.typingSearch = true
+				m.searchInput.SetValue(m.searchQuery)
+				m.searchInput.CursorEnd()
+				return m, m.searchInput.Focus()
+
+			case "J":
+				if item, ok := m.list.SelectedItem().(postItem); ok && m.currentScreen == screenSearch {
+					return m.jumpToPost(item.post)
+				}
+
+			case "t":
+				if m.filterable() {
+					return m.openTagPicker(), nil
+				}
+
+			case "esc":
+				// Only act once the text filter is gone
+				if m.list.FilterState() != list.Unfiltered {
+					break
+				}
+				if m.currentScreen == screenSearch {
+					m = m.switchScreen(screenInbox)
+					m.list.ResetSelected()
+					return m, m.reloadCmd()
+				}
+				if m.feedFilter != nil || m.tagFilter != "" {
+					m.feedFilter = nil
+					m.tagFilter = ""
+					return m, m.reloadCmd()
+				}
+
+			case "o":
+				if m.filterable() {
+					m.sort = m.sort.Next()
+					return m, m.reloadCmd()
 				}
 
 			case "G":
 				m.list.Select(len(m.list.Items()) - 1)
-				return m, nil
+				return m.maybeLoadMore()
+
+			case "]":
+				return m.jumpFeed(false)
+
+			case "[":
+				return m.jumpFeed(true)
 
 			case "x":
 				if m.currentScreen == screenArchive {
 					return m, nil
 				}
 				if item, ok := m.list.SelectedItem().(postItem); ok {
-					return m, archivePostCmd(m.ctx, m.queries, item.post.ID)
+					m.lastAction = &undoAction{kind: undoArchive, post: item.post, index: m.list.GlobalIndex()}
+					return m.changePost(item.post, asArchived, archivePostCmd(m.ctx, m.queries, item.post.ID))
 				}
 
+			case "d":
+				if item, ok := m.list.SelectedItem().(postItem); ok {
+					post := item.post
+					m.deletingPost = &post
+					return m, nil
+				}
+
+			case "X":
+				// Open, archive and move on in one go; the archive waits for
+				// the browser so a post that failed to open stays put
+				if m.currentScreen == screenArchive {
+					return m, nil
+				}
+				if item, ok := m.list.SelectedItem().(postItem); ok {
+					m.archiveOnOpen = item.post.ID
+					return m, m.openBrowserCmd(item.post, openURL(item.post))
+				}
+
+			case "?":
+				m.view = viewHelp
+				return m, nil
+
+			case "U", "ctrl+z":
+				return m.undo()
+
+			case "z":
+				// Only the inbox and New screen hide snoozed posts
+				if m.currentScreen != screenInbox && m.currentScreen != screenNew {
+					break
+				}
+				if item, ok := m.list.SelectedItem().(postItem); ok {
+					post := item.post
+					m.snoozing = &post
+					return m, nil
+				}
+
+			case "A":
+				if m.currentScreen == screenInbox && len(m.list.Items()) > 0 {
+					m.confirmArchiveAll = true
+					return m, nil
+				}
+
+			case "O":
+				posts := m.visiblePosts()
+				if len(posts) > openAllThreshold {
+					m.confirmOpenAll = true
+					return m, nil
+				}
+				return m, m.openAllCmd(posts)
+
 			case "u":
 				if m.currentScreen == screenArchive {
 					if item, ok := m.list.SelectedItem().(postItem); ok {
-						return m, unarchivePostCmd(m.ctx, m.queries, item.post.ID)
+						m.lastAction = &undoAction{kind: undoUnarchive, post: item.post, index: m.list.GlobalIndex()}
+						return m.changePost(item.post, asUnarchived, unarchivePostCmd(m.ctx, m.queries, item.post.ID))
 					}
 				}
 				if m.currentScreen == screenStarred {
 					if item, ok := m.list.SelectedItem().(postItem); ok {
-						return m, unstarPostCmd(m.ctx, m.queries, item.post.ID)
+						m.lastAction = &undoAction{kind: undoUnstar, post: item.post, index: m.list.GlobalIndex()}
+						return m.changePost(item.post, asUnstarred, unstarPostCmd(m.ctx, m.queries, item.post.ID, true))
 					}
 				}
 
 			case "s":
 				if m.currentScreen != screenStarred {
 					if item, ok := m.list.SelectedItem().(postItem); ok {
-						return m, starPostCmd(m.ctx, m.queries, item.post.ID)
+						m.lastAction = &undoAction{kind: undoStar, post: item.post, index: m.list.GlobalIndex()}
+						return m.changePost(item.post, asStarred, starPostCmd(m.ctx, m.queries, item.post.ID))
 					}
 				}
 
 			case "enter":
 				if item, ok := m.list.SelectedItem().(postItem); ok {
-					go openBrowser(item.post.Url)
+					if m.readingMode && m.currentScreen != screenArchive {
+						m.archiveOnOpen = item.post.ID
+					}
+					return m, m.openBrowserCmd(item.post, openURL(item.post))
+				}
+				return m, nil
+
+			case "R":
+				m.readingMode = !m.readingMode
+				return m, nil
+
+			case "r":
+				if m.refreshing {
+					return m, nil
+				}
+				m.refreshing = true
+				return m, tea.Batch(m.spinner.Tick, refreshCmd(m.ctx, m.queries, m.fetchOpts))
+
+			case "y":
+				if item, ok := m.list.SelectedItem().(postItem); ok {
+					return m, copyURLCmd(m.ctx, item.post.Url)
+				}
+
+			case "m":
+				if item, ok := m.list.SelectedItem().(postItem); ok {
+					if item.isRead() {
+						return m.changePost(item.post, asUnread, markUnreadCmd(m.ctx, m.queries, item.post.ID))
+					}
+					return m.changePost(item.post, asRead, markReadCmd(m.ctx, m.queries, item.post.ID))
+				}
+
+			case "v", " ":
+				if item, ok := m.list.SelectedItem().(postItem); ok {
+					return m.openDetail(item.post)
 				}
 				return m, nil
 			}
 		}
 	}
 
+	// Keep the search cursor blinking
+	var inputCmd tea.Cmd
+	if m.typingSearch {
+		m.searchInput, inputCmd = m.searchInput.Update(msg)
+	}
+
 	// Let the list handle all other keys
 	var cmd tea.Cmd
 	m.list, cmd = m.list.Update(msg)
-	return m, cmd
+	m, moreCmd := m.maybeLoadMore()
+	return m, tea.Batch(cmd, inputCmd, moreCmd)
+}
+
+// unreadStatus renders the unread summary shown after the post count
+func unreadStatus(unread database.UnreadCountRow) string {
+	feeds := "feeds"
+	if unread.Feeds == 1 {
+		feeds = "feed"
+	}
+	return fmt.Sprintf(" · %d unread · %d %s", unread.Unread, unread.Feeds, feeds)
 }
 
 func formatDate(dateStr string) string {
@@ -457,16 +1180,19 @@ func formatDate(dateStr string) string {
 		return dateStr
 	}
 
+	// Compare calendar days in local time rather than elapsed hours, so a
+	// post from 01:00 two days ago isn't "yesterday"
+	t = t.Local()
 	now := time.Now()
-	diff := now.Sub(t)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
 
 	switch {
-	case diff < 24*time.Hour:
-		if now.Day() == t.Day() {
-			return "today"
-		}
+	case day.Equal(today):
+		return "today"
+	case day.Equal(today.AddDate(0, 0, -1)):
 		return "yesterday"
-	case diff < 7*24*time.Hour:
+	case day.Before(today) && day.After(today.AddDate(0, 0, -7)):
 		return t.Format("Monday")
 	case t.Year() == now.Year():
 		return t.Format("Jan 02")
@@ -475,7 +1201,27 @@ func formatDate(dateStr string) string {
 	}
 }
 
+// The smallest terminal the list and its columns can be drawn in
+const (
+	minWidth  = 50
+	minHeight = 10
+)
+
 func (m model) View() string {
+	// The size is unknown until the first WindowSizeMsg
+	if m.width > 0 && (m.width < minWidth || m.height < minHeight) {
+		return tooSmallStyle.Width(m.width).Render(
+			fmt.Sprintf("Terminal too small, need at least %d×%d", minWidth, minHeight),
+		)
+	}
+
+	if m.view == viewDetail {
+		return m.detailView()
+	}
+	if m.view == viewHelp {
+		return m.helpView()
+	}
+
 	switch m.currentScreen {
 	case screenInbox:
 		m.list.Title = "📬 Inbox"
@@ -483,9 +1229,163 @@ func (m model) View() string {
 		m.list.Title = "📦 Archive"
 	case screenStarred:
 		m.list.Title = "⭐ Starred"
+	case screenFeeds:
+		m.list.Title = "📡 Feeds"
+	case screenSearch:
+		m.list.Title = "🔍 Search " + feedNameStyle.Render(m.searchQuery)
+	case screenNew:
+		m.list.Title = "✨ New today"
+		if !m.newSince.IsZero() {
+			m.list.Title = "✨ New since " + feedNameStyle.Render(m.newSince.Local().Format("Jan 02 15:04"))
+		}
+		m.list.Title += dateStyle.Render(" · c when caught up")
+	}
+
+	switch {
+	case m.picker == pickerFeed:
+		m.list.Title = "📡 Show posts from" + dateStyle.Render(" · enter to pick, esc to cancel")
+	case m.picker == pickerTag:
+		m.list.Title = "🏷 Show posts tagged" + dateStyle.Render(" · enter to pick, esc to cancel")
+	case m.typingSearch:
+		m.list.Title = "🔍 Search " + m.searchInput.View()
+	case m.filterable():
+		if m.feedFilter != nil {
+			m.list.Title += " " + feedNameStyle.Render(m.feedFilter.Name)
+		}
+		if m.tagFilter != "" {
+			m.list.Title += " " + feedNameStyle.Render("#"+m.tagFilter)
+		}
+	}
+
+	switch {
+	case !m.filterable() || m.picker != pickerNone || m.typingSearch:
+	case m.sort == database.SortOldest:
+		m.list.Title += dateStyle.Render(" · oldest first")
+	case m.sort == database.SortFeed:
+		m.list.Title += dateStyle.Render(" · by feed")
+	case m.sort == database.SortFirstSeen:
+		m.list.Title += dateStyle.Render(" · first seen")
+	}
+
+	if m.readingMode && m.currentScreen != screenArchive && m.currentScreen != screenFeeds {
+		m.list.Title += cursorStyle.Render(" · reading, enter opens and archives")
+	}
+
+	if m.pendingDelete != nil {
+		m.list.Title += " " + feedErrorStyle.Render(
+			fmt.Sprintf("Delete %q and all its posts? (y/n)", m.pendingDelete.Name),
+		)
+	}
+
+	if m.snoozing != nil {
+		m.list.Title += " " + feedErrorStyle.Render(snoozePrompt())
+	}
+
+	if m.deletingPost != nil {
+		m.list.Title += " " + feedErrorStyle.Render(m.deletePrompt())
 	}
 
-	return m.list.View()
+	if m.confirmArchiveAll {
+		m.list.Title += " " + feedErrorStyle.Render(
+			fmt.Sprintf("Archive all %d posts? (y/n)", len(m.list.Items())),
+		)
+	}
+
+	if m.confirmOpenAll {
+		m.list.Title += " " + feedErrorStyle.Render(
+			fmt.Sprintf("Open all %d posts in the browser? (y/n)", len(m.visiblePosts())),
+		)
+	}
+
+	if m.refreshing {
+		m.list.Title += " " + m.spinner.View() + dateStyle.Render("refreshing…")
+	}
+
+	return m.tabBar() + "\n" + m.list.View()
+}
+
+// openBrowserCmd opens url, the post's link or its enclosure, in the
+// configured browser or else the platform default; the post is only marked
+// read once that worked
+func (m model) openBrowserCmd(post database.PostWithFeed, url string) tea.Cmd {
+	if len(m.browser) == 0 {
+		return func() tea.Msg {
+			return openBrowserMsg{postID: post.ID, err: openBrowser(url)}
+		}
+	}
+
+	// The browser may be a text-mode one like w3m, so it gets the terminal
+	// until it exits
+	name := m.browser[0]
+	args := append(m.browser[1:len(m.browser):len(m.browser)], url)
+	return tea.ExecProcess(exec.Command(name, args...), func(err error) tea.Msg {
+		if err != nil {
+			err = fmt.Errorf("%s: %w", name, err)
+		}
+		return openBrowserMsg{postID: post.ID, err: err}
+	})
+}
+
+// selectPendingPost moves the cursor to the pending post if it is in the
+// list, leaving it where it is otherwise
+func (m model) selectPendingPost() model {
+	if m.pendingSelect == 0 {
+		return m
+	}
+	for i, item := range m.list.Items() {
+		if item, ok := item.(postItem); ok && item.post.ID == m.pendingSelect {
+			m.list.Select(i)
+			break
+		}
+	}
+	m.pendingSelect = 0
+	return m
+}
+
+// nextPostID returns the post below postID in the list, or the one above
+// it when postID is the last. With unread, read posts are skipped.
+func (m model) nextPostID(postID int64, unread bool) int64 {
+	items := m.list.VisibleItems()
+	index := slices.IndexFunc(items, func(item list.Item) bool {
+		post, ok := item.(postItem)
+		return ok && post.post.ID == postID
+	})
+	if index < 0 {
+		return 0
+	}
+	// Below first, then upwards from just above
+	above := slices.Clone(items[:index])
+	slices.Reverse(above)
+	for _, item := range slices.Concat(items[index+1:], above) {
+		if item, ok := item.(postItem); ok && !(unread && item.isRead()) {
+			return item.post.ID
+		}
+	}
+	return 0
+}
+
+// openAllThreshold is how many posts "O" opens before asking first
+const openAllThreshold = 10
+
+// visiblePosts returns the posts the list shows, honoring its filter
+func (m model) visiblePosts() []database.PostWithFeed {
+	var posts []database.PostWithFeed
+	for _, item := range m.list.VisibleItems() {
+		if item, ok := item.(postItem); ok {
+			posts = append(posts, item.post)
+		}
+	}
+	return posts
+}
+
+// openAllCmd opens posts one after the other, so the browser isn't asked
+// for dozens of tabs at the same instant
+func (m model) openAllCmd(posts []database.PostWithFeed) tea.Cmd {
+	cmds := make([]tea.Cmd, len(posts))
+	for i, post := range posts {
+		cmds[i] = m.openBrowserCmd(post, openURL(post))
+	}
+	return tea.Sequence(cmds...)
 }
 
 // openBrowser opens the specified URL in the default browser
@@ -499,27 +1399,64 @@ func openBrowser(url string) error {
 	case "darwin":
 		cmd = "open"
 	case "windows":
-		cmd = "cmd"
-		args = []string{"/c", "start"}
+		// cmd /c start treats & in query strings as a command separator
+		cmd = "rundll32"
+		args = []string{"url.dll,FileProtocolHandler"}
 	default:
 		return fmt.Errorf("unsupported platform")
 	}
 
 	args = append(args, url)
-	return exec.Command(cmd, args...).Start()
+	// Only a launcher that can't be started is reported: where it runs the
+	// browser in the foreground, waiting for it would hold up the post
+	// until the browser closed. Its output is left alone, the browser may
+	// hold on to it.
+	launcher := exec.Command(cmd, args...)
+	if err := launcher.Start(); err != nil {
+		return fmt.Errorf("%s: %w", cmd, err)
+	}
+	go launcher.Wait()
+	return nil
 }
 
-// Run starts the TUI application
-func Run(ctx context.Context, queries *database.Queries) error {
-	posts, err := queries.ListInbox(ctx)
+// Run starts the TUI application, refreshing feeds with opts, opening
+// posts with browserCommand when it isn't empty and drawing in theme. Feed
+// names in the post list are truncated to feedWidth columns.
+func Run(
+	ctx context.Context,
+	queries *database.Queries,
+	opts fetch.Options,
+	sort database.PostSort,
+	browserCommand string,
+	theme Theme,
+	feedWidth int,
+) error {
+	useTheme(theme)
+
+	posts, err := queries.ListInbox(ctx, database.ListOptions{Sort: sort})
 	if err != nil {
 		return fmt.Errorf("failed to fetch posts: %w", err)
 	}
+	unread, err := queries.UnreadCount(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count unread posts: %w", err)
+	}
+
+	screen, postID, err := queries.LastView(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read the last view: %w", err)
+	}
 
-	p := tea.NewProgram(
-		InitialModel(ctx, queries, posts),
-		tea.WithAltScreen(),
-	)
-	_, err = p.Run()
-	return err
+	m := InitialModel(ctx, queries, opts, sort, browserCommand, feedWidth, posts, unread)
+	p := tea.NewProgram(m.restoreView(screen, postID), tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		return err
+	}
+	if m, ok := final.(model); ok {
+		if err := saveLastView(m, queries); err != nil {
+			return fmt.Errorf("failed to save the last view: %w", err)
+		}
+	}
+	return nil
 }
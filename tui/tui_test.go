@@ -0,0 +1,49 @@
+package tui
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aaronzipp/feeder/database"
+)
+
+func TestUnstarPostCmd(t *testing.T) {
+	for _, archive := range []bool{true, false} {
+		ctx := context.Background()
+		db, err := database.Open(ctx, t.TempDir()+"/feeder.db")
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		defer db.Close()
+		queries := database.New(db)
+
+		_, err = db.Exec(`
+			insert into feed (id, name, url, feed_type) values (1, 'Blog', 'https://example.com/feed', 'rss');
+			insert into post (id, title, url, published_at, feed_id, guid, is_starred)
+			values (1, 'Post', 'https://example.com/1', '2024-01-01T00:00:00Z', 1, '1', 1);
+		`)
+		if err != nil {
+			t.Fatalf("inserting post: %v", err)
+		}
+
+		msg, ok := unstarPostCmd(ctx, queries, 1, archive)().(unstarPostMsg)
+		if !ok {
+			t.Fatalf("unstarPostCmd didn't return an unstarPostMsg")
+		}
+		if msg.err != nil {
+			t.Errorf("unstarPostCmd(archive=%v) err = %v, want nil", archive, msg.err)
+		}
+
+		var starred, archived int64
+		err = db.QueryRow("select is_starred, is_archived from post where id = 1").Scan(&starred, &archived)
+		if err != nil {
+			t.Fatalf("reading post: %v", err)
+		}
+		if starred != 0 {
+			t.Errorf("unstarPostCmd(archive=%v) left the post starred", archive)
+		}
+		if want := map[bool]int64{true: 1, false: 0}[archive]; archived != want {
+			t.Errorf("unstarPostCmd(archive=%v) is_archived = %d, want %d", archive, archived, want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"github.com/aaronzipp/feeder/database"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// undoKind is a post action that U can reverse
+type undoKind int
+
+const (
+	undoArchive undoKind = iota
+	undoUnarchive
+	undoStar
+	undoUnstar
+)
+
+func (k undoKind) String() string {
+	switch k {
+	case undoArchive:
+		return "archive"
+	case undoUnarchive:
+		return "unarchive"
+	case undoStar:
+		return "star"
+	case undoUnstar:
+		return "unstar"
+	default:
+		return "unknown"
+	}
+}
+
+// undoAction is the last archive or star change, kept so a stray key can
+// be taken back. post is how the post was before, to show again at index
+// when the change took it off the list, or -1 when it wasn't listed.
+type undoAction struct {
+	kind  undoKind
+	post  database.PostWithFeed
+	index int
+}
+
+// switchScreen moves to screen; the undo state belongs to the screen it
+// was made on, so it is dropped, and the archive starts again from its
+// first page
+func (m model) switchScreen(screen screenType) model {
+	m.currentScreen = screen
+	m.lastAction = nil
+	m.archiveLimit = archivePageSize
+	return m
+}
+
+// undo reverses the last action, if there is one
+func (m model) undo() (model, tea.Cmd) {
+	if m.lastAction == nil {
+		return m, nil
+	}
+	action := *m.lastAction
+	m.lastAction = nil
+
+	postID := action.post.ID
+	var cmd tea.Cmd
+	switch action.kind {
+	case undoArchive:
+		cmd = unarchivePostCmd(m.ctx, m.queries, postID)
+	case undoUnarchive:
+		cmd = archivePostCmd(m.ctx, m.queries, postID)
+	case undoStar:
+		cmd = unstarPostCmd(m.ctx, m.queries, postID, false)
+	case undoUnstar:
+		cmd = starPostCmd(m.ctx, m.queries, postID)
+		// The Starred screen's unstar archived the post as well
+		if !(postItem{post: action.post}).isArchived() {
+			cmd = tea.Batch(cmd, unarchivePostCmd(m.ctx, m.queries, postID))
+		}
+	}
+	status := m.list.NewStatusMessage(dateStyle.Render("Undid " + action.kind.String()))
+	// A post that had already left the list when it was changed can only
+	// come back with the screen, once it's stored
+	if action.index < 0 {
+		return m, tea.Batch(tea.Sequence(cmd, m.reloadCmd()), status)
+	}
+	m, listCmd := m.setPost(action.post, action.index)
+	return m, tea.Batch(cmd, listCmd, status)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aaronzipp/feeder/database"
+	"github.com/aaronzipp/feeder/fetch"
+)
+
+// importURLList implements `feeder import -format txt <file>`, subscribing
+// to every URL in a file listing one per line. Blank lines and lines
+// starting with # are skipped. Each line's outcome is printed as it's
+// imported, and a URL that fails doesn't stop the ones after it.
+func importURLList(ctx context.Context, queries *database.Queries, opts fetch.Options, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	added, skipped, failed := 0, 0, 0
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		url := strings.TrimSpace(scanner.Text())
+		if url == "" || strings.HasPrefix(url, "#") {
+			continue
+		}
+
+		result, err := importURL(ctx, queries, opts, url)
+		switch {
+		case err != nil:
+			fmt.Printf("%s:%d: failed: %s\n", path, line, err)
+			failed++
+		case result == "":
+			fmt.Printf("%s:%d: already subscribed to %s\n", path, line, url)
+			skipped++
+		default:
+			fmt.Printf("%s:%d: %s\n", path, line, result)
+			added++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	fmt.Printf("Imported %d feeds, skipped %d, failed %d\n", added, skipped, failed)
+	return nil
+}
+
+// importURL subscribes to the feed at url, or the one it links to when it's
+// a page, and describes what was added. It returns "" when the feed is
+// already subscribed to.
+func importURL(ctx context.Context, queries *database.Queries, opts fetch.Options, url string) (string, error) {
+	if subscribed, err := isSubscribed(ctx, queries, url); err != nil || subscribed {
+		return "", err
+	}
+
+	detected, err := fetch.Detect(ctx, opts, url, fetch.Auth{})
+	if err != nil {
+		return "", fmt.Errorf("failed to detect feed type: %w", err)
+	}
+	if detected.URL != url {
+		if subscribed, err := isSubscribed(ctx, queries, detected.URL); err != nil || subscribed {
+			return "", err
+		}
+	}
+
+	name := detected.Title
+	if name == "" {
+		name = detected.URL
+	}
+	err = queries.CreateFeed(ctx, database.CreateFeedParams{
+		Name:     name,
+		Url:      detected.URL,
+		FeedType: detected.Type,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to add feed: %w", err)
+	}
+	if detected.URL != url {
+		return fmt.Sprintf("added %s feed %q found on %s", detected.Type, name, url), nil
+	}
+	return fmt.Sprintf("added %s feed %q", detected.Type, name), nil
+}
+
+func isSubscribed(ctx context.Context, queries *database.Queries, url string) (bool, error) {
+	_, err := queries.GetFeedByURL(ctx, url)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to look up feed: %w", err)
+	}
+}